@@ -0,0 +1,124 @@
+package gitgo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// DivergenceState classifies a branch against its upstream
+//
+// DivergenceState 表示分支相对其上游的分类
+type DivergenceState string
+
+const (
+	DivergenceUpToDate DivergenceState = "up-to-date" // Same commit as upstream // 与上游为同一提交
+	DivergenceAhead    DivergenceState = "ahead"      // Upstream fast-forwards to the branch, Push works // 上游可快进到该分支，可以 Push
+	DivergenceBehind   DivergenceState = "behind"     // Branch fast-forwards to upstream, Pull works // 分支可快进到上游，可以 Pull
+	DivergenceDiverged DivergenceState = "diverged"   // Both sides have own commits, needs merge or rebase // 双方都有各自的提交，需要合并或变基
+)
+
+// BranchDivergence is the comparison of a branch with its upstream
+//
+// BranchDivergence 是分支与其上游的比较结果
+type BranchDivergence struct {
+	Upstream string          // Upstream short name like "origin/main" // 上游短名称，如 "origin/main"
+	Ahead    int             // Commits on the branch missing upstream // 分支上有而上游没有的提交数
+	Behind   int             // Commits on upstream missing the branch // 上游有而分支上没有的提交数
+	State    DivergenceState // Classification // 分类
+}
+
+// AheadBehind counts commits reachable from a but not b (ahead) and from b but not a (behind)
+// Use case: show how far a branch moved away from a base
+//
+// AheadBehind 统计从 a 可达而 b 不可达（ahead）以及从 b 可达而 a 不可达（behind）的提交数
+// 使用场景：展示分支相对基准偏离了多少
+func (G *Gcm) AheadBehind(a, b string) (ahead int, behind int, err error) {
+	output, err := G.execStdout("git", "rev-list", "--left-right", "--count", a+"..."+b)
+	if err != nil {
+		return 0, 0, erero.Wro(err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, erero.Errorf("wrong rev-list count output %q", output)
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, erero.Wro(err)
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, erero.Wro(err)
+	}
+	return ahead, behind, nil
+}
+
+// MergeBase gets the best common ancestor commit of the refs, blank when they share no history
+// Needs at least two refs
+//
+// MergeBase 获取这些引用的最佳共同祖先提交，没有共同历史时为空
+// 至少需要两个引用
+func (G *Gcm) MergeBase(refs ...string) (string, error) {
+	if len(refs) < 2 {
+		return "", erero.New("merge base needs at least two refs")
+	}
+	output, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NO-MERGE-BASE"), "git", append([]string{"merge-base"}, refs...)...)
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	if exc == 1 {
+		return "", nil
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// IsAncestor checks if a is an ancestor of b, a commit counts as its own ancestor
+// Use case: check that b fast-forwards from a
+//
+// IsAncestor 检查 a 是否是 b 的祖先，提交本身也算作自己的祖先
+// 使用场景：检查 b 是否可从 a 快进
+func (G *Gcm) IsAncestor(a, b string) (bool, error) {
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NOT-ANCESTOR"), "git", "merge-base", "--is-ancestor", a, b)
+	if err != nil {
+		return false, erero.Wro(err)
+	}
+	return exc == 0, nil
+}
+
+// Divergence compares the branch with its upstream, blank branch means the current one
+// Fails when the branch has no upstream
+// Use case: check fast-forward before calling Push and Pull
+//
+// Divergence 比较分支与其上游，branch 为空表示当前分支
+// 分支没有上游时失败
+// 使用场景：在调用 Push 和 Pull 前检查能否快进
+func (G *Gcm) Divergence(branch string) (*BranchDivergence, error) {
+	upstream, err := G.GetUpstreamBranch(branch)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	local := branch
+	if local == "" {
+		local = "HEAD"
+	}
+	ahead, behind, err := G.AheadBehind(local, upstream)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var state DivergenceState
+	switch {
+	case ahead == 0 && behind == 0:
+		state = DivergenceUpToDate
+	case behind == 0:
+		state = DivergenceAhead
+	case ahead == 0:
+		state = DivergenceBehind
+	default:
+		state = DivergenceDiverged
+	}
+	return &BranchDivergence{
+		Upstream: upstream,
+		Ahead:    ahead,
+		Behind:   behind,
+		State:    state,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_AheadBehind tests ahead/behind counts, merge bases and ancestry between branches
+// Verifies unrelated histories have no merge base
+//
+// TestGcm_AheadBehind 测试分支之间的领先/落后数、合并基点和祖先关系
+// 验证无关历史没有合并基点
+func TestGcm_AheadBehind(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-ancestry-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	base := rese.V1(gcm.GetCurrentCommitHash())
+	gcm.CheckoutNewBranch("feature").Done()
+	for _, name := range []string{"b.txt", "c.txt"} {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, name), []byte(name), 0644))
+		gcm.Add().Commit(name).Done()
+	}
+	gcm.Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "d.txt"), []byte("d"), 0644))
+	gcm.Add().Commit("main work").Done()
+
+	ahead, behind, err := gcm.AheadBehind("feature", "main")
+	require.NoError(t, err)
+	require.Equal(t, 2, ahead)
+	require.Equal(t, 1, behind)
+
+	require.Equal(t, base, rese.V1(gcm.MergeBase("feature", "main")))
+	require.Equal(t, base, rese.V1(gcm.MergeBase("feature", "main", base)))
+	_, err = gcm.MergeBase("main")
+	require.Error(t, err)
+
+	require.True(t, rese.V1(gcm.IsAncestor(base, "feature")))
+	require.True(t, rese.V1(gcm.IsAncestor("main", "main")))
+	require.False(t, rese.V1(gcm.IsAncestor("feature", "main")))
+	_, err = gcm.IsAncestor("missing", "main")
+	require.Error(t, err)
+
+	orphanPath := filepath.Join(tempDIR, "orphan")
+	must.Done(os.MkdirAll(orphanPath, 0755))
+	orphan := gitgo.New(orphanPath)
+	orphan.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(orphanPath, "o.txt"), []byte("o"), 0644))
+	orphan.Add().Commit("orphan").Done()
+	gcm.RemoteAdd("orphan", orphanPath).Fetch("orphan").Done()
+	require.Empty(t, rese.V1(gcm.MergeBase("main", "orphan/main")))
+}
+
+// TestGcm_Divergence tests classifying branches against their upstreams
+// Verifies up-to-date, ahead, behind and diverged states
+//
+// TestGcm_Divergence 测试对照上游对分支进行分类
+// 验证最新、领先、落后和分叉状态
+func TestGcm_Divergence(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-divergence-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	sourcePath := filepath.Join(tempDIR, "source")
+	must.Done(os.MkdirAll(sourcePath, 0755))
+
+	source := gitgo.New(sourcePath)
+	source.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "a.txt"), []byte("a"), 0644))
+	source.Add().Commit("initial").Done()
+
+	workPath := filepath.Join(tempDIR, "work")
+	work := rese.P1(gitgo.Clone(sourcePath, workPath, gitgo.CloneOptions{}))
+	divergence := rese.P1(work.Divergence(""))
+	require.Equal(t, "origin/main", divergence.Upstream)
+	require.Equal(t, gitgo.DivergenceUpToDate, divergence.State)
+	idle := rese.P1(gitgo.Clone(sourcePath, filepath.Join(tempDIR, "idle"), gitgo.CloneOptions{}))
+
+	must.Done(os.WriteFile(filepath.Join(workPath, "b.txt"), []byte("b"), 0644))
+	work.Add().Commit("local work").Done()
+	divergence = rese.P1(work.Divergence("main"))
+	require.Equal(t, gitgo.DivergenceAhead, divergence.State)
+	require.Equal(t, 1, divergence.Ahead)
+
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "c.txt"), []byte("c"), 0644))
+	source.Add().Commit("upstream work").Done()
+	work.Fetch("origin").Done()
+	divergence = rese.P1(work.Divergence("main"))
+	require.Equal(t, gitgo.DivergenceDiverged, divergence.State)
+	require.Equal(t, 1, divergence.Ahead)
+	require.Equal(t, 1, divergence.Behind)
+
+	idle.Fetch("origin").Done()
+	divergence = rese.P1(idle.Divergence(""))
+	require.Equal(t, gitgo.DivergenceBehind, divergence.State)
+	require.Equal(t, 1, divergence.Behind)
+
+	work.CheckoutNewBranch("local").Done()
+	_, err := work.Divergence("local")
+	require.Error(t, err)
+}
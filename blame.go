@@ -0,0 +1,199 @@
+package gitgo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// BlameOptions configures Blame, zero value blames each line of the work tree file
+//
+// BlameOptions 配置 Blame，零值表示追溯工作区文件的每一行
+type BlameOptions struct {
+	Revision       string // Blame the file at this revision, blank means the work tree // 在此修订处追溯文件，为空表示工作区
+	StartLine      int    // First line, 1-based, 0 means line 1 (-L) // 起始行，从 1 开始，0 表示第 1 行（-L）
+	EndLine        int    // Last line, 0 means the file end (-L) // 结束行，0 表示文件末尾（-L）
+	DetectMoves    bool   // Follow lines moved within the file (-M) // 跟踪文件内移动的行（-M）
+	DetectCopies   bool   // Follow lines moved or copied from other files (-C) // 跟踪从其他文件移动或复制的行（-C）
+	IgnoreRevsFile string // File listing revisions to skip, like formatting commits (--ignore-revs-file) // 列出要跳过的修订的文件，如格式化提交（--ignore-revs-file）
+}
+
+// BlameLine is one line of a file with the commit that last changed it
+// Lines not committed yet get the zero hash with the "Not Committed Yet" author
+//
+// BlameLine 是文件中的一行及最后修改它的提交
+// 尚未提交的行为零哈希，作者为 "Not Committed Yet"
+type BlameLine struct {
+	Hash         string    // Commit hash // 提交哈希
+	OriginalLine int       // Line number in the commit's version of the file // 在该提交版本文件中的行号
+	FinalLine    int       // Line number in the blamed version of the file // 在被追溯版本文件中的行号
+	Author       string    // Author name // 作者名称
+	AuthorEmail  string    // Author email without angle brackets // 不带尖括号的作者邮箱
+	AuthorTime   time.Time // Author time in the author's zone // 作者时区下的作者时间
+	Summary      string    // Commit subject // 提交主题
+	Filename     string    // File path in the commit, differs after renames and copies // 提交中的文件路径，重命名和复制后会不同
+	PreviousHash string    // Parent commit holding the line's prior version, blank when none // 持有该行先前版本的父提交，没有时为空
+	PreviousPath string    // File path in the previous commit // 先前提交中的文件路径
+	Boundary     bool      // Commit is a boundary, like the root commit // 提交是边界提交，如根提交
+	Content      string    // Line text // 行文本
+}
+
+// blameCommit holds the commit headers --porcelain prints once per commit
+//
+// blameCommit 保存 --porcelain 对每个提交只输出一次的头部信息
+type blameCommit struct {
+	author       string
+	authorEmail  string
+	authorTime   int64
+	authorZone   string
+	summary      string
+	filename     string
+	previousHash string
+	previousPath string
+	boundary     bool
+}
+
+// Blame attributes each line of the file to the commit that last changed it
+// Parses git blame --porcelain, path is relative to the Gcm path
+// Use case: code ownership reports and finding who to ask about a line
+//
+// Blame 将文件的每一行归属到最后修改它的提交
+// 解析 git blame --porcelain，path 相对于 Gcm 路径
+// 使用场景：代码归属报告，以及查找某行代码该询问谁
+func (G *Gcm) Blame(path string, opts BlameOptions) ([]BlameLine, error) {
+	args := []string{"blame", "--porcelain"}
+	if opts.StartLine > 0 || opts.EndLine > 0 {
+		lineRange := strconv.Itoa(max(opts.StartLine, 1)) + ","
+		if opts.EndLine > 0 {
+			lineRange += strconv.Itoa(opts.EndLine)
+		}
+		args = append(args, "-L", lineRange)
+	}
+	if opts.DetectMoves {
+		args = append(args, "-M")
+	}
+	if opts.DetectCopies {
+		args = append(args, "-C")
+	}
+	if opts.IgnoreRevsFile != "" {
+		args = append(args, "--ignore-revs-file", opts.IgnoreRevsFile)
+	}
+	if opts.Revision != "" {
+		args = append(args, opts.Revision)
+	}
+	output, err := G.execStdout("git", append(args, "--", path)...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseBlamePorcelain(string(output))
+}
+
+// parseBlamePorcelain parses git blame --porcelain output into blame lines
+// Each line starts with "<hash> <orig> <final> [<count>]", the commit headers follow only at first sight of the hash
+// When a commit blames lines of multiple paths (like with -C), git repeats "previous" and "filename" at each group,
+// so a "filename" without its own "previous" clears the previous commit of the earlier path
+//
+// parseBlamePorcelain 将 git blame --porcelain 输出解析为追溯行
+// 每行以 "<hash> <orig> <final> [<count>]" 开头，提交头部只在首次出现该哈希时跟随
+// 当提交追溯到多个路径的行时（如使用 -C），git 在每组重复 "previous" 和 "filename"，
+// 因此没有自身 "previous" 的 "filename" 会清除之前路径的先前提交
+func parseBlamePorcelain(output string) ([]BlameLine, error) {
+	var lines []BlameLine
+	commits := map[string]*blameCommit{}
+	var line *BlameLine
+	var commit *blameCommit
+	var previousHash, previousPath string // "previous" of the current header group // 当前头部组的 "previous"
+	for _, text := range strings.Split(output, "\n") {
+		if line == nil {
+			if text == "" {
+				continue
+			}
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				return nil, erero.Errorf("wrong blame header %q", text)
+			}
+			originalLine, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			finalLine, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			line = &BlameLine{Hash: fields[0], OriginalLine: originalLine, FinalLine: finalLine}
+			previousHash, previousPath = "", ""
+			if commit = commits[line.Hash]; commit == nil {
+				commit = &blameCommit{}
+				commits[line.Hash] = commit
+			}
+			continue
+		}
+		if content, ok := strings.CutPrefix(text, "\t"); ok {
+			line.Content = content
+			line.Author = commit.author
+			line.AuthorEmail = commit.authorEmail
+			line.AuthorTime = blameTime(commit.authorTime, commit.authorZone)
+			line.Summary = commit.summary
+			line.Filename = commit.filename
+			line.PreviousHash = commit.previousHash
+			line.PreviousPath = commit.previousPath
+			line.Boundary = commit.boundary
+			lines = append(lines, *line)
+			line = nil
+			continue
+		}
+		key, value, _ := strings.Cut(text, " ")
+		switch key {
+		case "author":
+			commit.author = value
+		case "author-mail":
+			commit.authorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			authorTime, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, erero.Wro(err)
+			}
+			commit.authorTime = authorTime
+		case "author-tz":
+			commit.authorZone = value
+		case "summary":
+			commit.summary = value
+		case "previous":
+			previousHash, previousPath, _ = strings.Cut(value, " ")
+			previousPath = unquoteDiffPath(previousPath)
+		case "boundary":
+			commit.boundary = true
+		case "filename":
+			// Git writes "previous" right before "filename", both belong to the path of this group
+			// git 在 "filename" 之前写入 "previous"，两者都属于该组的路径
+			commit.filename = unquoteDiffPath(value)
+			commit.previousHash, commit.previousPath = previousHash, previousPath
+		}
+	}
+	if line != nil {
+		return nil, erero.Errorf("blame line %d misses its content", line.FinalLine)
+	}
+	return lines, nil
+}
+
+// blameTime converts the unix seconds and "+0800" style zone into a time
+//
+// blameTime 将 unix 秒数和 "+0800" 格式的时区转换为时间
+func blameTime(seconds int64, zone string) time.Time {
+	value := time.Unix(seconds, 0)
+	if len(zone) != 5 {
+		return value
+	}
+	hours, err1 := strconv.Atoi(zone[1:3])
+	minutes, err2 := strconv.Atoi(zone[3:5])
+	if err1 != nil || err2 != nil {
+		return value
+	}
+	offset := hours*3600 + minutes*60
+	if zone[0] == '-' {
+		offset = -offset
+	}
+	return value.In(time.FixedZone(zone, offset))
+}
@@ -0,0 +1,173 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Blame tests per-line attribution with authors, times, summaries and previous commits
+// Verifies line ranges, blame at a revision, ignored revisions and uncommitted lines
+//
+// TestGcm_Blame 测试带有作者、时间、摘要和先前提交的逐行归属
+// 验证行范围、在指定修订处追溯、忽略修订以及未提交的行
+func TestGcm_Blame(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-blame-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	commitAs := func(author, date, content, message string) string {
+		t.Setenv("GIT_AUTHOR_NAME", author)
+		t.Setenv("GIT_AUTHOR_EMAIL", author+"@example.com")
+		t.Setenv("GIT_AUTHOR_DATE", date)
+		must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte(content), 0644))
+		gcm.Add().Commit(message).Done()
+		return rese.V1(gcm.GetCurrentCommitHash())
+	}
+	first := commitAs("alice", "2030-01-01T08:00:00+08:00", "one\ntwo\nthree\n", "add lines")
+	second := commitAs("bob", "2031-06-01T00:00:00-05:00", "one\nTWO\nthree\nfour\n", "shout two\n\nwith body")
+
+	lines := rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{}))
+	require.Len(t, lines, 4)
+	require.Equal(t, []string{first, second, first, second}, []string{lines[0].Hash, lines[1].Hash, lines[2].Hash, lines[3].Hash})
+	require.Equal(t, []string{"one", "TWO", "three", "four"}, []string{lines[0].Content, lines[1].Content, lines[2].Content, lines[3].Content})
+
+	line := lines[2]
+	require.Equal(t, 3, line.OriginalLine)
+	require.Equal(t, 3, line.FinalLine)
+	require.Equal(t, "alice", line.Author)
+	require.Equal(t, "alice@example.com", line.AuthorEmail)
+	require.Equal(t, "2030-01-01T08:00:00+08:00", line.AuthorTime.Format("2006-01-02T15:04:05-07:00"))
+	require.Equal(t, "add lines", line.Summary)
+	require.Equal(t, "a.txt", line.Filename)
+	require.True(t, line.Boundary)
+	require.Empty(t, line.PreviousHash)
+
+	line = lines[1]
+	require.Equal(t, "bob", line.Author)
+	require.Equal(t, "2031-06-01T00:00:00-05:00", line.AuthorTime.Format("2006-01-02T15:04:05-07:00"))
+	require.Equal(t, "shout two", line.Summary)
+	require.Equal(t, first, line.PreviousHash)
+	require.Equal(t, "a.txt", line.PreviousPath)
+	require.False(t, line.Boundary)
+
+	lines = rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{StartLine: 2, EndLine: 3}))
+	require.Len(t, lines, 2)
+	require.Equal(t, 2, lines[0].FinalLine)
+	require.Equal(t, "three", lines[1].Content)
+	lines = rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{StartLine: 4}))
+	require.Len(t, lines, 1)
+	require.Equal(t, "four", lines[0].Content)
+
+	lines = rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{Revision: first}))
+	require.Len(t, lines, 3)
+	require.Equal(t, "two", lines[1].Content)
+	require.Equal(t, first, lines[1].Hash)
+
+	ignorePath := filepath.Join(tempDIR, "ignore-revs")
+	must.Done(os.WriteFile(ignorePath, []byte(second+"\n"), 0644))
+	lines = rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{IgnoreRevsFile: ignorePath, EndLine: 2}))
+	require.Equal(t, first, lines[1].Hash)
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("one\nTWO\nthree\nfour\nfive\n"), 0644))
+	lines = rese.V1(gcm.Blame("a.txt", gitgo.BlameOptions{}))
+	require.Len(t, lines, 5)
+	require.Equal(t, strings.Repeat("0", 40), lines[4].Hash)
+	require.Equal(t, "Not Committed Yet", lines[4].Author)
+
+	_, err := gcm.Blame("missing.txt", gitgo.BlameOptions{})
+	require.Error(t, err)
+}
+
+// TestGcm_Blame_DetectMoves tests -M and -C following lines moved within and across files
+//
+// TestGcm_Blame_DetectMoves 测试 -M 和 -C 跟踪文件内和跨文件移动的行
+func TestGcm_Blame_DetectMoves(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-blame-moves-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	block := "func alpha() { return computeAlphaValue() }\nfunc beta() { return computeBetaValue() }\n"
+	other := "func gamma() { return computeGammaValue() }\nfunc delta() { return computeDeltaValue() }\n"
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.go"), []byte(block+other), 0644))
+	gcm.Add().Commit("add funcs").Done()
+	first := rese.V1(gcm.GetCurrentCommitHash())
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.go"), []byte(other+block), 0644))
+	gcm.Add().Commit("reorder funcs").Done()
+	moved := rese.V1(gcm.Blame("a.go", gitgo.BlameOptions{}))
+	followed := rese.V1(gcm.Blame("a.go", gitgo.BlameOptions{DetectMoves: true}))
+	require.NotEqual(t, []string{first, first, first, first}, []string{moved[0].Hash, moved[1].Hash, moved[2].Hash, moved[3].Hash})
+	require.Equal(t, []string{first, first, first, first}, []string{followed[0].Hash, followed[1].Hash, followed[2].Hash, followed[3].Hash})
+	require.Equal(t, 1, followed[2].OriginalLine)
+	require.Equal(t, 3, followed[2].FinalLine)
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.go"), []byte(other), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b.go"), []byte(block), 0644))
+	gcm.Add().Commit("split funcs").Done()
+	split := rese.V1(gcm.GetCurrentCommitHash())
+	require.Equal(t, split, rese.V1(gcm.Blame("b.go", gitgo.BlameOptions{}))[0].Hash)
+	copied := rese.V1(gcm.Blame("b.go", gitgo.BlameOptions{DetectCopies: true}))
+	require.Equal(t, first, copied[0].Hash)
+	require.Equal(t, "a.go", copied[0].Filename)
+}
+
+// TestGcm_Blame_CopiesFromTwoFiles tests -C with lines copied from two files changed in one commit
+// Verifies each path group keeps its own filename and previous commit
+//
+// TestGcm_Blame_CopiesFromTwoFiles 测试 -C 下从同一提交修改的两个文件复制的行
+// 验证每个路径组保留自己的文件名和先前提交
+func TestGcm_Blame_CopiesFromTwoFiles(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-blame-copies-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	writeFile := func(name, content string) {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, name), []byte(content), 0644))
+	}
+	writeFile("x.go", "package x\n")
+	gcm.Add().Commit("add x").Done()
+	base := rese.V1(gcm.GetCurrentCommitHash())
+
+	// One commit grows x.go and creates y.go // 同一提交扩充 x.go 并创建 y.go
+	blockX := "func alpha() { return computeAlphaValue() }\nfunc beta() { return computeBetaValue() }\n"
+	blockY := "func gamma() { return computeGammaValue() }\nfunc delta() { return computeDeltaValue() }\n"
+	writeFile("x.go", "package x\n"+blockX)
+	writeFile("y.go", blockY)
+	gcm.Add().Commit("add blocks").Done()
+	blocks := rese.V1(gcm.GetCurrentCommitHash())
+
+	// z.go copies both blocks, x.go and y.go change in the same commit so -C looks at them
+	// z.go 复制两个代码块，x.go 和 y.go 在同一提交中修改，使 -C 检查它们
+	writeFile("z.go", blockX+blockY)
+	writeFile("x.go", "package x\n"+blockX+"// x\n")
+	writeFile("y.go", blockY+"// y\n")
+	gcm.Add().Commit("copy blocks").Done()
+
+	lines := rese.V1(gcm.Blame("z.go", gitgo.BlameOptions{DetectCopies: true}))
+	require.Len(t, lines, 4)
+	for _, line := range lines {
+		require.Equal(t, blocks, line.Hash)
+	}
+	require.Equal(t, []string{"x.go", "x.go", "y.go", "y.go"}, []string{lines[0].Filename, lines[1].Filename, lines[2].Filename, lines[3].Filename})
+	require.Equal(t, base, lines[1].PreviousHash)
+	require.Equal(t, "x.go", lines[1].PreviousPath)
+	// y.go is new in the commit, so it has no previous commit // y.go 在该提交中新建，因此没有先前提交
+	require.Empty(t, lines[2].PreviousHash)
+	require.Empty(t, lines[3].PreviousPath)
+}
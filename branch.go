@@ -0,0 +1,223 @@
+package gitgo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// DeleteBranch deletes the local branch, force also deletes branches not merged into their upstream or HEAD
+// Use case: clean up feature branches after merge
+//
+// DeleteBranch 删除本地分支，force 时也删除未合并到其上游或 HEAD 的分支
+// 使用场景：合并后清理特性分支
+func (G *Gcm) DeleteBranch(name string, force bool) *Gcm {
+	if force {
+		return G.do("git", "branch", "-D", name)
+	}
+	return G.do("git", "branch", "-d", name)
+}
+
+// RenameBranch renames the local branch along with its config and reflog
+// Fails when the new name exists
+//
+// RenameBranch 重命名本地分支及其配置和引用日志
+// 新名称已存在时失败
+func (G *Gcm) RenameBranch(oldName, newName string) *Gcm {
+	return G.do("git", "branch", "-m", oldName, newName)
+}
+
+// CopyBranch copies the local branch along with its config and reflog to a new name
+// Fails when the new name exists
+// Use case: keep a backup before rewriting branch history
+//
+// CopyBranch 将本地分支及其配置和引用日志复制到新名称
+// 新名称已存在时失败
+// 使用场景：在改写分支历史前保留备份
+func (G *Gcm) CopyBranch(srcName, dstName string) *Gcm {
+	return G.do("git", "branch", "-c", srcName, dstName)
+}
+
+// SetUpstream sets the upstream of the local branch to remoteBranch like "origin/main"
+// Use case: track a remote branch pushed without -u
+//
+// SetUpstream 将本地分支的上游设置为 remoteBranch，如 "origin/main"
+// 使用场景：跟踪未使用 -u 推送的远程分支
+func (G *Gcm) SetUpstream(branch, remoteBranch string) *Gcm {
+	return G.do("git", "branch", "--set-upstream-to="+remoteBranch, branch)
+}
+
+// UnsetUpstream removes the upstream of the local branch
+//
+// UnsetUpstream 移除本地分支的上游
+func (G *Gcm) UnsetUpstream(branch string) *Gcm {
+	return G.do("git", "branch", "--unset-upstream", branch)
+}
+
+// DeleteRemoteBranch deletes the branch on the remote
+// Use case: remove merged branches from the shared remote
+//
+// DeleteRemoteBranch 删除远程上的分支
+// 使用场景：从共享远程仓库删除已合并的分支
+func (G *Gcm) DeleteRemoteBranch(remote, name string) *Gcm {
+	return G.do("git", "push", remote, "--delete", name)
+}
+
+// MergedBranches gets local branches whose tips are reachable from into, blank into means HEAD
+// The into branch itself is included
+// Use case: find branches safe to delete
+//
+// MergedBranches 获取提交可从 into 到达的本地分支，into 为空表示 HEAD
+// 包含 into 分支本身
+// 使用场景：查找可以安全删除的分支
+func (G *Gcm) MergedBranches(into string) ([]string, error) {
+	return G.listBranchesMerged("--merged", into)
+}
+
+// UnmergedBranches gets local branches whose tips are not reachable from into, blank into means HEAD
+// Use case: find branches with work not yet merged
+//
+// UnmergedBranches 获取提交无法从 into 到达的本地分支，into 为空表示 HEAD
+// 使用场景：查找还有未合并工作的分支
+func (G *Gcm) UnmergedBranches(into string) ([]string, error) {
+	return G.listBranchesMerged("--no-merged", into)
+}
+
+// listBranchesMerged lists local branch names filtered with --merged or --no-merged
+//
+// listBranchesMerged 列出使用 --merged 或 --no-merged 过滤的本地分支名称
+func (G *Gcm) listBranchesMerged(filter, into string) ([]string, error) {
+	if into == "" {
+		into = "HEAD"
+	}
+	output, err := G.execStdout("git", "branch", "--format=%(refname:short)", filter, into)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var results []string
+	for _, name := range strings.Split(string(output), "\n") {
+		// Detached HEAD shows up as "(HEAD detached at ...)" // 游离 HEAD 显示为 "(HEAD detached at ...)"
+		if name = strings.TrimSpace(name); name != "" && !strings.HasPrefix(name, "(") {
+			results = append(results, name)
+		}
+	}
+	return results, nil
+}
+
+// branchInfoFormat is the for-each-ref --format of ListBranchInfos, fields split by NUL and records by newline
+//
+// branchInfoFormat 是 ListBranchInfos 使用的 for-each-ref --format 格式，字段以 NUL 分隔，记录以换行分隔
+const branchInfoFormat = "%(refname)%00%(symref)%00%(HEAD)%00%(objectname)%00%(committerdate:iso-strict)%00%(upstream:short)%00%(upstream:track,nobracket)%00%(contents:subject)"
+
+// branchInfoFieldCount is the count of NUL separated fields in each record of branchInfoFormat
+//
+// branchInfoFieldCount 是 branchInfoFormat 中每条记录以 NUL 分隔的字段数量
+const branchInfoFieldCount = 8
+
+// BranchInfo is one typed local or remote branch with tip and tracking info
+//
+// BranchInfo 是一个带有最新提交和跟踪信息的类型化本地或远程分支
+type BranchInfo struct {
+	Name          string    // Short name like "main" or "origin/main" // 短名称，如 "main" 或 "origin/main"
+	Ref           string    // Full ref like "refs/heads/main" // 完整引用，如 "refs/heads/main"
+	Remote        bool      // Remote-tracking branch // 远程跟踪分支
+	Current       bool      // Checked out as HEAD // 作为 HEAD 检出
+	Hash          string    // Tip commit hash // 最新提交哈希
+	Subject       string    // Tip commit subject // 最新提交主题
+	CommitterDate time.Time // Tip committer date // 最新提交的提交者日期
+	Upstream      string    // Upstream short name, blank when unset // 上游短名称，未设置时为空
+	Ahead         int       // Commits ahead of upstream // 领先上游的提交数
+	Behind        int       // Commits behind upstream // 落后上游的提交数
+	UpstreamGone  bool      // Upstream is configured but gone from the remote // 已配置上游但其已从远程消失
+}
+
+// ListBranchInfos gets typed local and remote branches, most recently committed first
+// Returns tip hash, subject, committer date, upstream, ahead/behind and upstream-gone state
+// Remote HEAD symbolic refs like "origin/HEAD" are skipped
+// Use case: show stale branches on dashboards, like ones not committed for 90 days
+//
+// ListBranchInfos 获取类型化的本地和远程分支，最近提交的在前
+// 返回最新提交哈希、主题、提交者日期、上游、领先/落后数和上游消失状态
+// 跳过 "origin/HEAD" 这类远程 HEAD 符号引用
+// 使用场景：在仪表盘上展示过期分支，如 90 天未提交的分支
+func (G *Gcm) ListBranchInfos() ([]BranchInfo, error) {
+	output, err := G.execStdout("git", "for-each-ref", "--sort=-committerdate", "--format="+branchInfoFormat, "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseBranchInfos(output)
+}
+
+// parseBranchInfos parses the records of branchInfoFormat into branch infos
+//
+// parseBranchInfos 将 branchInfoFormat 的记录解析为分支信息
+func parseBranchInfos(output []byte) ([]BranchInfo, error) {
+	var infos []BranchInfo
+	for _, line := range strings.Split(string(output), "\n") {
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\x00", branchInfoFieldCount)
+		if len(fields) != branchInfoFieldCount {
+			return nil, erero.Errorf("wrong branch info field count %d", len(fields))
+		}
+		if fields[1] != "" {
+			continue // Symbolic ref like origin/HEAD // 符号引用，如 origin/HEAD
+		}
+		committerDate, err := time.Parse(time.RFC3339, fields[4])
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		info := BranchInfo{
+			Ref:           fields[0],
+			Current:       fields[2] == "*",
+			Hash:          fields[3],
+			CommitterDate: committerDate,
+			Upstream:      fields[5],
+			Subject:       fields[7],
+		}
+		if name, ok := strings.CutPrefix(info.Ref, "refs/remotes/"); ok {
+			info.Name, info.Remote = name, true
+		} else {
+			info.Name = strings.TrimPrefix(info.Ref, "refs/heads/")
+		}
+		if info.UpstreamGone, info.Ahead, info.Behind, err = parseUpstreamTrack(fields[6]); err != nil {
+			return nil, erero.Wro(err)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// parseUpstreamTrack parses %(upstream:track,nobracket) like "ahead 1, behind 2" and "gone"
+//
+// parseUpstreamTrack 解析 %(upstream:track,nobracket)，如 "ahead 1, behind 2" 和 "gone"
+func parseUpstreamTrack(track string) (gone bool, ahead int, behind int, err error) {
+	if track == "gone" {
+		return true, 0, 0, nil
+	}
+	for _, part := range strings.Split(track, ", ") {
+		if part == "" {
+			continue
+		}
+		kind, count, ok := strings.Cut(part, " ")
+		if !ok {
+			return false, 0, 0, erero.Errorf("wrong upstream track %q", track)
+		}
+		number, err := strconv.Atoi(count)
+		if err != nil {
+			return false, 0, 0, erero.Wro(err)
+		}
+		switch kind {
+		case "ahead":
+			ahead = number
+		case "behind":
+			behind = number
+		default:
+			return false, 0, 0, erero.Errorf("wrong upstream track %q", track)
+		}
+	}
+	return false, ahead, behind, nil
+}
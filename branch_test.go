@@ -0,0 +1,125 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_BranchLifecycle tests deleting, renaming, copying and upstream handling of branches
+// Verifies merged and unmerged queries and remote branch deletion
+//
+// TestGcm_BranchLifecycle 测试分支的删除、重命名、复制和上游处理
+// 验证已合并和未合并查询以及远程分支删除
+func TestGcm_BranchLifecycle(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-branch-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	repoPath := filepath.Join(tempDIR, "repo")
+	must.Done(os.MkdirAll(repoPath, 0755))
+
+	gcm := gitgo.New(repoPath)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	gcm.CheckoutNewBranch("merged").Checkout("main").Done()
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(repoPath, "b.txt"), []byte("b"), 0644))
+	gcm.Add().Commit("feature work").Checkout("main").Done()
+
+	require.Equal(t, []string{"main", "merged"}, rese.V1(gcm.MergedBranches("")))
+	require.Equal(t, []string{"feature"}, rese.V1(gcm.UnmergedBranches("main")))
+	require.Equal(t, []string{"feature", "main", "merged"}, rese.V1(gcm.MergedBranches("feature")))
+	require.Empty(t, rese.V1(gcm.UnmergedBranches("feature")))
+
+	require.Error(t, gcm.DeleteBranch("feature", false).Reason())
+	gcm.CopyBranch("feature", "feature-backup").DeleteBranch("feature", true).DeleteBranch("merged", false).Done()
+	require.False(t, rese.V1(gcm.BranchExists("feature")))
+	require.True(t, rese.V1(gcm.BranchExists("feature-backup")))
+
+	gcm.RenameBranch("feature-backup", "topic").Done()
+	require.False(t, rese.V1(gcm.BranchExists("feature-backup")))
+	require.Equal(t, []string{"topic"}, rese.V1(gcm.UnmergedBranches("")))
+
+	remotePath := filepath.Join(tempDIR, "remote.git")
+	rese.P1(gitgo.Clone(repoPath, remotePath, gitgo.CloneOptions{Bare: true}))
+	gcm.RemoteAdd("origin", remotePath).Fetch("origin").Done()
+
+	gcm.SetUpstream("topic", "origin/topic").Done()
+	require.Equal(t, "origin/topic", rese.V1(gcm.GetUpstreamBranch("topic")))
+	gcm.UnsetUpstream("topic").Done()
+	require.Error(t, gcm.UnsetUpstream("topic").Reason())
+
+	remote := gitgo.New(remotePath)
+	require.True(t, rese.V1(remote.BranchExists("topic")))
+	gcm.DeleteRemoteBranch("origin", "topic").Done()
+	require.False(t, rese.V1(remote.BranchExists("topic")))
+}
+
+// TestGcm_ListBranchInfos tests typed local and remote branches sorted by recency
+// Verifies tip info, upstream, ahead/behind counts and gone upstreams
+//
+// TestGcm_ListBranchInfos 测试按最近提交排序的类型化本地和远程分支
+// 验证最新提交信息、上游、领先/落后数和已消失的上游
+func TestGcm_ListBranchInfos(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-branch-infos-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	sourcePath := filepath.Join(tempDIR, "source")
+	must.Done(os.MkdirAll(sourcePath, 0755))
+
+	source := gitgo.New(sourcePath)
+	source.Init().Done()
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00Z")
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "a.txt"), []byte("a"), 0644))
+	source.Add().Commit("initial").CheckoutNewBranch("old").Done()
+	t.Setenv("GIT_COMMITTER_DATE", "2020-02-01T00:00:00Z")
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "old.txt"), []byte("old"), 0644))
+	source.Add().Commit("old work").Checkout("main").Done()
+
+	work := rese.P1(gitgo.Clone(sourcePath, filepath.Join(tempDIR, "work"), gitgo.CloneOptions{}))
+	work.Checkout("old").Checkout("main").Done()
+	t.Setenv("GIT_COMMITTER_DATE", "2021-01-01T00:00:00Z")
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "work", "b.txt"), []byte("b"), 0644))
+	work.Add().Commit("local work").Done()
+
+	infos := rese.V1(work.ListBranchInfos())
+	require.Len(t, infos, 4)
+	require.Equal(t, "main", infos[0].Name)
+	require.Equal(t, "refs/heads/main", infos[0].Ref)
+	require.True(t, infos[0].Current)
+	require.False(t, infos[0].Remote)
+	require.Equal(t, rese.V1(work.GetCurrentCommitHash()), infos[0].Hash)
+	require.Equal(t, "local work", infos[0].Subject)
+	require.Equal(t, 2021, infos[0].CommitterDate.Year())
+	require.Equal(t, "origin/main", infos[0].Upstream)
+	require.Equal(t, 1, infos[0].Ahead)
+	require.Equal(t, 0, infos[0].Behind)
+	require.Equal(t, 2020, infos[3].CommitterDate.Year())
+	require.Equal(t, time.January, infos[3].CommitterDate.Month())
+
+	byName := map[string]*gitgo.BranchInfo{}
+	for idx := range infos {
+		byName[infos[idx].Name] = &infos[idx]
+	}
+	require.Contains(t, byName, "origin/old")
+	require.True(t, byName["origin/old"].Remote)
+	require.Equal(t, "old work", byName["origin/old"].Subject)
+	require.Equal(t, "origin/old", byName["old"].Upstream)
+	require.False(t, byName["old"].UpstreamGone)
+
+	work.DeleteRemoteBranch("origin", "old").Done()
+	infos = rese.V1(work.ListBranchInfos())
+	require.Len(t, infos, 3)
+	for _, info := range infos {
+		require.Equal(t, info.Name == "old", info.UpstreamGone)
+	}
+}
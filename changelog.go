@@ -0,0 +1,408 @@
+package gitgo
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// conventionalHeaderRegexp matches Conventional Commits headers like "feat(auth)!: add login"
+//
+// conventionalHeaderRegexp 匹配 Conventional Commits 头部，如 "feat(auth)!: add login"
+var conventionalHeaderRegexp = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]+)\))?(!)?: (.+)$`)
+
+// issueRefRegexp matches issue references like "#12", not inside words like "abc#12"
+//
+// issueRefRegexp 匹配 "#12" 这类问题引用，不匹配 "abc#12" 这类单词内的引用
+var issueRefRegexp = regexp.MustCompile(`(?:^|[^\w&/])#(\d+)\b`)
+
+// changelogTypeTitles maps commit types to group titles, in rendering sequence
+//
+// changelogTypeTitles 将提交类型映射到分组标题，按渲染顺序排列
+var changelogTypeTitles = []struct {
+	Type  string
+	Title string
+}{
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance Improvements"},
+	{"refactor", "Code Refactoring"},
+	{"revert", "Reverts"},
+	{"docs", "Documentation"},
+	{"style", "Styles"},
+	{"test", "Tests"},
+	{"build", "Build System"},
+	{"ci", "Continuous Integration"},
+	{"chore", "Chores"},
+	{ChangelogOtherType, "Other Changes"},
+}
+
+// keepAChangelogSections maps commit types to Keep a Changelog sections, in rendering sequence
+// Types missing here, like docs and chore, stay out of Keep a Changelog output
+//
+// keepAChangelogSections 将提交类型映射到 Keep a Changelog 小节，按渲染顺序排列
+// 不在此处的类型（如 docs 和 chore）不会出现在 Keep a Changelog 输出中
+var keepAChangelogSections = []struct {
+	Section string
+	Types   []string
+}{
+	{"Added", []string{"feat"}},
+	{"Changed", []string{"perf", "refactor", "revert"}},
+	{"Fixed", []string{"fix"}},
+}
+
+// ChangelogOtherType is the type of commits not following Conventional Commits
+//
+// ChangelogOtherType 是不遵循 Conventional Commits 的提交的类型
+const ChangelogOtherType = "other"
+
+// ChangelogOptions selects the commits of Changelog
+//
+// ChangelogOptions 选择 Changelog 的提交
+type ChangelogOptions struct {
+	From      string // Start ref (excluded), blank means the latest tag or highest TagPrefix version reachable from To^ // 起始引用（不包含），为空表示从 To^ 可到达的最新标签或最高的 TagPrefix 版本
+	To        string // End ref (included), blank means HEAD // 结束引用（包含），为空表示 HEAD
+	TagPrefix string // Monorepo component tag prefix like "auth/" // monorepo 组件标签前缀，如 "auth/"
+	Path      string // Limit to commits touching this path, blank means the TagPrefix dir like "auth" // 限制为涉及此路径的提交，为空表示 TagPrefix 目录，如 "auth"
+	Version   string // Release title, blank means "Unreleased" // 发布标题，为空表示 "Unreleased"
+	IssueURL  string // Issue link base like "https://github.com/org/repo/issues/", blank means no links // 问题链接前缀，如 "https://github.com/org/repo/issues/"，为空表示不生成链接
+}
+
+// ChangelogEntry is one commit parsed as a Conventional Commit
+// Commits not following the spec get ChangelogOtherType with the whole header as Subject
+//
+// ChangelogEntry 是解析为 Conventional Commit 的一个提交
+// 不遵循规范的提交类型为 ChangelogOtherType，整个头部作为 Subject
+type ChangelogEntry struct {
+	Hash         string    `json:"hash"`                   // Full commit hash // 完整提交哈希
+	Type         string    `json:"type"`                   // Lowercase type like "feat" // 小写类型，如 "feat"
+	Scope        string    `json:"scope,omitempty"`        // Scope like "auth", blank when none // 范围，如 "auth"，没有时为空
+	Subject      string    `json:"subject"`                // Description after the type // 类型之后的描述
+	Body         string    `json:"body,omitempty"`         // Message text after the header // 头部之后的消息文本
+	Breaking     bool      `json:"breaking"`               // Marked with "!" or a BREAKING CHANGE footer // 带有 "!" 或 BREAKING CHANGE 脚注
+	BreakingNote string    `json:"breakingNote,omitempty"` // BREAKING CHANGE footer text, the subject when marked with "!" only // BREAKING CHANGE 脚注文本，仅带 "!" 时为主题
+	Issues       []string  `json:"issues,omitempty"`       // Referenced issue numbers // 引用的问题编号
+	Author       string    `json:"author"`                 // Author name // 作者名称
+	Time         time.Time `json:"time"`                   // Author time // 作者时间
+}
+
+// ChangelogGroup holds the entries of one commit type
+//
+// ChangelogGroup 保存一种提交类型的条目
+type ChangelogGroup struct {
+	Type    string           `json:"type"`    // Commit type // 提交类型
+	Title   string           `json:"title"`   // Title like "Features" // 标题，如 "Features"
+	Entries []ChangelogEntry `json:"entries"` // Entries, newest first // 条目，最新的在前
+}
+
+// Changelog is the release notes of the commits between two refs
+//
+// Changelog 是两个引用之间提交的发布说明
+type Changelog struct {
+	Version  string           `json:"version"`        // Release title // 发布标题
+	From     string           `json:"from,omitempty"` // Start ref, blank when starting at the root commit // 起始引用，从根提交开始时为空
+	To       string           `json:"to"`             // End ref // 结束引用
+	Date     time.Time        `json:"date"`           // Committer time of the end ref // 结束引用的提交时间
+	Breaking []ChangelogEntry `json:"breaking"`       // Breaking entries of each group // 各分组中的破坏性条目
+	Groups   []ChangelogGroup `json:"groups"`         // Groups in type sequence, blank groups left out // 按类型顺序排列的分组，省略空分组
+	issueURL string           // Issue link base // 问题链接前缀
+}
+
+// Changelog builds release notes from the Conventional Commits between two refs, merges left out
+// Use case: generate release notes instead of editing GetLogOneLine output by hand
+//
+// Changelog 根据两个引用之间的 Conventional Commits 生成发布说明，不含合并提交
+// 使用场景：生成发布说明，而不是手动编辑 GetLogOneLine 的输出
+func (G *Gcm) Changelog(opts ChangelogOptions) (*Changelog, error) {
+	to := opts.To
+	if to == "" {
+		to = "HEAD"
+	}
+	from := opts.From
+	if from == "" {
+		var err error
+		if from, err = G.getChangelogStart(opts.TagPrefix, to); err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+	path := opts.Path
+	if path == "" && strings.HasSuffix(opts.TagPrefix, "/") {
+		path = strings.TrimSuffix(opts.TagPrefix, "/")
+	}
+	revision := to
+	if from != "" {
+		revision = from + ".." + to
+	}
+	logOpts := LogOptions{Revisions: []string{revision}, NoMerges: true}
+	if path != "" {
+		logOpts.Paths = []string{path}
+	}
+	commits, err := G.Log(logOpts)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	tip, err := G.Log(LogOptions{Revisions: []string{to}, Limit: 1})
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if len(tip) == 0 {
+		return nil, erero.Errorf("ref %s has no commit", to)
+	}
+
+	changelog := &Changelog{
+		Version:  opts.Version,
+		From:     from,
+		To:       to,
+		Date:     tip[0].CommitterTime,
+		Breaking: []ChangelogEntry{}, // Empty, not nil, so JSON gets [] instead of null // 空切片而非 nil，使 JSON 输出 [] 而不是 null
+		Groups:   []ChangelogGroup{},
+		issueURL: opts.IssueURL,
+	}
+	if changelog.Version == "" {
+		changelog.Version = "Unreleased"
+	}
+	var entries []ChangelogEntry
+	for idx := range commits {
+		entries = append(entries, parseChangelogEntry(&commits[idx]))
+	}
+	for _, item := range changelogTypeTitles {
+		group := ChangelogGroup{Type: item.Type, Title: item.Title}
+		for _, entry := range entries {
+			if entry.Type == item.Type || (item.Type == ChangelogOtherType && !isChangelogType(entry.Type)) {
+				group.Entries = append(group.Entries, entry)
+			}
+		}
+		if len(group.Entries) > 0 {
+			changelog.Groups = append(changelog.Groups, group)
+		}
+	}
+	for _, group := range changelog.Groups {
+		for _, entry := range group.Entries {
+			if entry.Breaking {
+				changelog.Breaking = append(changelog.Breaking, entry)
+			}
+		}
+	}
+	return changelog, nil
+}
+
+// getChangelogStart gets the default start ref, the latest tag or highest TagPrefix version reachable from the parent of to
+// Starting at the parent lets to itself be a release tag, blank when to is a root commit or no tag is reachable
+//
+// getChangelogStart 获取默认的起始引用，即从 to 的父提交可到达的最新标签或最高的 TagPrefix 版本
+// 从父提交开始使 to 本身可以是发布标签，to 为根提交或没有可到达的标签时为空
+func (G *Gcm) getChangelogStart(tagPrefix, to string) (string, error) {
+	output, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "ROOT-COMMIT"), "git", "rev-parse", "-q", "--verify", to+"^{commit}^")
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	if exc == 1 {
+		return "", nil // Root commit without parent // 没有父提交的根提交
+	}
+	parent := strings.TrimSpace(string(output))
+	if tagPrefix != "" {
+		tags, err := G.ListTags(TagQuery{Merged: parent})
+		if err != nil {
+			return "", erero.Wro(err)
+		}
+		var latest *TagVersion
+		for _, tag := range tags {
+			if !strings.HasPrefix(tag, tagPrefix) {
+				continue
+			}
+			if version, err := ParseTagVersion(tag, tagPrefix); err == nil && (latest == nil || version.Compare(latest) > 0) {
+				latest = version
+			}
+		}
+		if latest == nil {
+			return "", nil
+		}
+		return latest.String(), nil
+	}
+	output, exc, err = G.execTake(G.execConfig.NewConfig().WithExpectExit(128, "NO-TAGS"), "git", "describe", "--tags", "--abbrev=0", parent)
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	if exc == 128 {
+		return "", nil // No tags reachable // 没有可到达的标签
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parseChangelogEntry parses the Conventional Commits header, footers and issue references of the commit
+//
+// parseChangelogEntry 解析提交的 Conventional Commits 头部、脚注和问题引用
+func parseChangelogEntry(commit *Commit) ChangelogEntry {
+	entry := ChangelogEntry{
+		Hash:    commit.Hash,
+		Type:    ChangelogOtherType,
+		Subject: commit.Subject,
+		Body:    commit.Body,
+		Author:  commit.AuthorName,
+		Time:    commit.AuthorTime,
+	}
+	if matches := conventionalHeaderRegexp.FindStringSubmatch(commit.Subject); matches != nil {
+		entry.Type = strings.ToLower(matches[1])
+		entry.Scope = matches[2]
+		entry.Breaking = matches[3] == "!"
+		entry.Subject = matches[4]
+	}
+	if note, ok := findBreakingNote(commit.Body); ok {
+		entry.Breaking, entry.BreakingNote = true, note
+	} else if entry.Breaking {
+		entry.BreakingNote = entry.Subject
+	}
+	for _, matches := range issueRefRegexp.FindAllStringSubmatch(commit.Subject+"\n"+commit.Body, -1) {
+		if !slices.Contains(entry.Issues, matches[1]) {
+			entry.Issues = append(entry.Issues, matches[1])
+		}
+	}
+	return entry
+}
+
+// findBreakingNote finds the "BREAKING CHANGE:" or "BREAKING-CHANGE:" footer, the note runs until a blank line
+//
+// findBreakingNote 查找 "BREAKING CHANGE:" 或 "BREAKING-CHANGE:" 脚注，说明持续到空行为止
+func findBreakingNote(body string) (string, bool) {
+	lines := strings.Split(body, "\n")
+	for idx, line := range lines {
+		note, ok := strings.CutPrefix(line, "BREAKING CHANGE:")
+		if !ok {
+			if note, ok = strings.CutPrefix(line, "BREAKING-CHANGE:"); !ok {
+				continue
+			}
+		}
+		parts := []string{strings.TrimSpace(note)}
+		for _, next := range lines[idx+1:] {
+			if strings.TrimSpace(next) == "" {
+				break
+			}
+			parts = append(parts, strings.TrimSpace(next))
+		}
+		return strings.Join(parts, " "), true
+	}
+	return "", false
+}
+
+// isChangelogType checks if the type has its own group
+//
+// isChangelogType 检查类型是否有自己的分组
+func isChangelogType(commitType string) bool {
+	for _, item := range changelogTypeTitles {
+		if item.Type == commitType && item.Type != ChangelogOtherType {
+			return true
+		}
+	}
+	return false
+}
+
+// Markdown renders the changelog in the conventional-changelog style
+// Breaking changes come first, then each group with scope, subject, issue links and short hash
+//
+// Markdown 以 conventional-changelog 风格渲染变更日志
+// 先列出破坏性变更，然后是包含范围、主题、问题链接和短哈希的各个分组
+func (c *Changelog) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("## " + c.Version + " (" + c.Date.Format(time.DateOnly) + ")\n")
+	if len(c.Breaking) > 0 {
+		sb.WriteString("\n### ⚠ BREAKING CHANGES\n\n")
+		for _, entry := range c.Breaking {
+			sb.WriteString("- " + c.formatScope(entry) + entry.BreakingNote + "\n")
+		}
+	}
+	for _, group := range c.Groups {
+		sb.WriteString("\n### " + group.Title + "\n\n")
+		for _, entry := range group.Entries {
+			sb.WriteString("- " + c.formatScope(entry) + entry.Subject + c.formatIssues(entry) + " (" + shortHash(entry.Hash) + ")\n")
+		}
+	}
+	return sb.String()
+}
+
+// KeepAChangelog renders the changelog as a release section of the Keep a Changelog format
+// Features go to Added, perf, refactor and reverts to Changed and fixes to Fixed
+// Breaking entries get a "**BREAKING**" mark, other types stay out
+//
+// KeepAChangelog 以 Keep a Changelog 格式渲染变更日志中的一个发布小节
+// 特性归入 Added，perf、refactor 和 revert 归入 Changed，修复归入 Fixed
+// 破坏性条目带有 "**BREAKING**" 标记，其他类型不输出
+func (c *Changelog) KeepAChangelog() string {
+	var sb strings.Builder
+	if c.Version == "Unreleased" {
+		sb.WriteString("## [Unreleased]\n")
+	} else {
+		sb.WriteString("## [" + c.Version + "] - " + c.Date.Format(time.DateOnly) + "\n")
+	}
+	for _, section := range keepAChangelogSections {
+		var lines []string
+		for _, group := range c.Groups {
+			if !slices.Contains(section.Types, group.Type) {
+				continue
+			}
+			for _, entry := range group.Entries {
+				line := "- "
+				if entry.Breaking {
+					line += "**BREAKING** "
+				}
+				lines = append(lines, line+c.formatScope(entry)+entry.Subject+c.formatIssues(entry)+"\n")
+			}
+		}
+		if len(lines) > 0 {
+			sb.WriteString("\n### " + section.Section + "\n\n" + strings.Join(lines, ""))
+		}
+	}
+	return sb.String()
+}
+
+// JSON exports the changelog as indented JSON
+//
+// JSON 将变更日志导出为缩进的 JSON
+func (c *Changelog) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return data, nil
+}
+
+// formatScope formats the scope as a bold "**scope:** " lead, blank when none
+//
+// formatScope 将范围格式化为加粗的 "**scope:** " 前缀，没有时为空
+func (c *Changelog) formatScope(entry ChangelogEntry) string {
+	if entry.Scope == "" {
+		return ""
+	}
+	return "**" + entry.Scope + ":** "
+}
+
+// formatIssues formats issue references, linked when the issue URL is set
+//
+// formatIssues 格式化问题引用，设置了问题链接时生成链接
+func (c *Changelog) formatIssues(entry ChangelogEntry) string {
+	if len(entry.Issues) == 0 {
+		return ""
+	}
+	refs := make([]string, 0, len(entry.Issues))
+	for _, issue := range entry.Issues {
+		if c.issueURL == "" {
+			refs = append(refs, "#"+issue)
+		} else {
+			refs = append(refs, "[#"+issue+"]("+c.issueURL+issue+")")
+		}
+	}
+	return " (" + strings.Join(refs, ", ") + ")"
+}
+
+// shortHash returns the 7 char abbreviation of the hash
+//
+// shortHash 返回哈希的 7 字符缩写
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
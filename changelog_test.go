@@ -0,0 +1,246 @@
+package gitgo_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Changelog tests parsing Conventional Commits since the latest tag into groups
+// Verifies scopes, breaking markers and footers, issue links and the three output formats
+//
+// TestGcm_Changelog 测试将最新标签以来的 Conventional Commits 解析为分组
+// 验证范围、破坏性标记和脚注、问题链接以及三种输出格式
+func TestGcm_Changelog(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-changelog-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	commit := func(name, message string) {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, name), []byte(message), 0644))
+		gcm.Add().Commit(message).Done()
+	}
+	commit("a.txt", "feat: initial release")
+	gcm.Tag("v1.0.0").Done()
+	commit("b.txt", "feat(api): add users endpoint (#12)")
+	commit("c.txt", "fix: handle blank names\n\nCloses #7 and #12")
+	commit("d.txt", "refactor(db)!: drop legacy tables")
+	commit("e.txt", "feat: switch config format\n\nBREAKING CHANGE: config moves to YAML\nJSON files stay unread")
+	commit("f.txt", "docs: explain setup")
+	commit("g.txt", "update readme")
+
+	changelog := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{Version: "v2.0.0", IssueURL: "https://example.com/issues/"}))
+	require.Equal(t, "v1.0.0", changelog.From)
+	require.Equal(t, "HEAD", changelog.To)
+	var titles []string
+	for _, group := range changelog.Groups {
+		titles = append(titles, group.Title)
+	}
+	require.Equal(t, []string{"Features", "Bug Fixes", "Code Refactoring", "Documentation", "Other Changes"}, titles)
+
+	features := changelog.Groups[0].Entries
+	require.Len(t, features, 2)
+	require.Equal(t, "switch config format", features[0].Subject)
+	require.True(t, features[0].Breaking)
+	require.Equal(t, "config moves to YAML JSON files stay unread", features[0].BreakingNote)
+	require.Equal(t, "api", features[1].Scope)
+	require.Equal(t, []string{"12"}, features[1].Issues)
+	require.False(t, features[1].Breaking)
+
+	fix := changelog.Groups[1].Entries[0]
+	require.Equal(t, []string{"7", "12"}, fix.Issues)
+	refactor := changelog.Groups[2].Entries[0]
+	require.True(t, refactor.Breaking)
+	require.Equal(t, "drop legacy tables", refactor.BreakingNote)
+	other := changelog.Groups[4].Entries[0]
+	require.Equal(t, gitgo.ChangelogOtherType, other.Type)
+	require.Equal(t, "update readme", other.Subject)
+	require.Len(t, changelog.Breaking, 2)
+
+	markdown := changelog.Markdown()
+	require.Contains(t, markdown, "## v2.0.0 (")
+	require.Contains(t, markdown, "### ⚠ BREAKING CHANGES\n\n- config moves to YAML JSON files stay unread\n- **db:** drop legacy tables\n")
+	require.Contains(t, markdown, "- **api:** add users endpoint (#12) ([#12](https://example.com/issues/12)) ("+features[1].Hash[:7]+")\n")
+	require.Contains(t, markdown, "### Other Changes\n\n- update readme (")
+
+	keep := changelog.KeepAChangelog()
+	require.Contains(t, keep, "## [v2.0.0] - ")
+	require.Contains(t, keep, "### Added\n\n- **BREAKING** switch config format\n- **api:** add users endpoint (#12) ([#12](https://example.com/issues/12))\n")
+	require.Contains(t, keep, "### Changed\n\n- **BREAKING** **db:** drop legacy tables\n")
+	require.Contains(t, keep, "### Fixed\n\n- handle blank names ([#7](https://example.com/issues/7), [#12](https://example.com/issues/12))\n")
+	require.NotContains(t, keep, "explain setup")
+
+	var decoded gitgo.Changelog
+	must.Done(json.Unmarshal(rese.V1(changelog.JSON()), &decoded))
+	require.Equal(t, "v2.0.0", decoded.Version)
+	require.Len(t, decoded.Groups, 5)
+	require.Equal(t, "api", decoded.Groups[0].Entries[1].Scope)
+
+	unreleased := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{From: "HEAD~1"}))
+	require.Equal(t, "Unreleased", unreleased.Version)
+	require.Len(t, unreleased.Groups, 1)
+	require.Equal(t, "## [Unreleased]\n", unreleased.KeepAChangelog())
+	require.Contains(t, unreleased.Markdown(), "- update readme (")
+}
+
+// TestGcm_Changelog_TagPrefix tests monorepo changelogs limited to the component path
+// Verifies the start falls back to the whole history when the component has no version tag
+//
+// TestGcm_Changelog_TagPrefix 测试限定于组件路径的 monorepo 变更日志
+// 验证组件没有版本标签时从整个历史开始
+func TestGcm_Changelog_TagPrefix(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-changelog-prefix-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	commit := func(path, message string) {
+		must.Done(os.MkdirAll(filepath.Join(tempDIR, filepath.Dir(path)), 0755))
+		must.Done(os.WriteFile(filepath.Join(tempDIR, path), []byte(message), 0644))
+		gcm.Add().Commit(message).Done()
+	}
+	commit("auth/a.txt", "feat(auth): add login")
+	commit("billing/a.txt", "feat(billing): add invoices")
+	gcm.Tag("auth/v1.2.0").Done()
+	commit("auth/b.txt", "fix(auth): expire sessions")
+	commit("billing/b.txt", "fix(billing): round totals")
+	gcm.Tag("v9.0.0").Done()
+
+	changelog := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{TagPrefix: "auth/"}))
+	require.Equal(t, "auth/v1.2.0", changelog.From)
+	require.Len(t, changelog.Groups, 1)
+	require.Len(t, changelog.Groups[0].Entries, 1)
+	require.Equal(t, "expire sessions", changelog.Groups[0].Entries[0].Subject)
+
+	changelog = rese.P1(gcm.Changelog(gitgo.ChangelogOptions{TagPrefix: "billing/"}))
+	require.Empty(t, changelog.From)
+	var subjects []string
+	for _, group := range changelog.Groups {
+		for _, entry := range group.Entries {
+			subjects = append(subjects, entry.Subject)
+		}
+	}
+	require.Equal(t, []string{"add invoices", "round totals"}, subjects)
+
+	_, err := gcm.Changelog(gitgo.ChangelogOptions{From: "missing"})
+	require.Error(t, err)
+}
+
+// TestGcm_Changelog_To tests changelogs of past releases, starting from the tag before To
+// Verifies reverts land in Changed and empty lists stay arrays in JSON
+//
+// TestGcm_Changelog_To 测试过去发布的变更日志，从 To 之前的标签开始
+// 验证 revert 归入 Changed，且空列表在 JSON 中保持为数组
+func TestGcm_Changelog_To(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-changelog-to-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	commit := func(path, message string) {
+		must.Done(os.MkdirAll(filepath.Join(tempDIR, filepath.Dir(path)), 0755))
+		must.Done(os.WriteFile(filepath.Join(tempDIR, path), []byte(message), 0644))
+		gcm.Add().Commit(message).Done()
+	}
+	commit("auth/a.txt", "feat(auth): add login")
+	gcm.Tag("auth/v1.0.0").Done()
+	commit("a.txt", "feat: initial release")
+	gcm.Tag("v1.0.0").Done()
+	commit("b.txt", "fix: handle blank names")
+	commit("auth/b.txt", "revert(auth): drop remember me")
+	gcm.Tag("v1.1.0").Tag("auth/v1.1.0").Done()
+	commit("c.txt", "feat: add export")
+
+	changelog := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{To: "v1.1.0", Version: "v1.1.0"}))
+	require.Equal(t, "v1.0.0", changelog.From)
+	require.Equal(t, "v1.1.0", changelog.To)
+	var subjects []string
+	for _, group := range changelog.Groups {
+		for _, entry := range group.Entries {
+			subjects = append(subjects, entry.Subject)
+		}
+	}
+	require.Equal(t, []string{"handle blank names", "drop remember me"}, subjects)
+	require.Contains(t, changelog.KeepAChangelog(), "### Changed\n\n- **auth:** drop remember me\n")
+	require.NotContains(t, changelog.KeepAChangelog(), "### Removed")
+
+	data := string(rese.V1(changelog.JSON()))
+	require.Contains(t, data, `"breaking": []`)
+
+	changelog = rese.P1(gcm.Changelog(gitgo.ChangelogOptions{To: "auth/v1.0.0"}))
+	require.Empty(t, changelog.From) // Root commit, nothing before it // 根提交，之前没有内容
+	require.Len(t, changelog.Groups, 1)
+	require.Equal(t, "add login", changelog.Groups[0].Entries[0].Subject)
+
+	changelog = rese.P1(gcm.Changelog(gitgo.ChangelogOptions{To: "auth/v1.1.0", TagPrefix: "auth/"}))
+	require.Equal(t, "auth/v1.0.0", changelog.From)
+	require.Len(t, changelog.Groups, 1)
+	require.Equal(t, "drop remember me", changelog.Groups[0].Entries[0].Subject)
+
+	changelog = rese.P1(gcm.Changelog(gitgo.ChangelogOptions{From: "HEAD", To: "HEAD"}))
+	require.Empty(t, changelog.Groups)
+	data = string(rese.V1(changelog.JSON()))
+	require.Contains(t, data, `"breaking": []`)
+	require.Contains(t, data, `"groups": []`)
+
+	_, err := gcm.Changelog(gitgo.ChangelogOptions{To: "missing"})
+	require.Error(t, err)
+}
+
+// TestGcm_Changelog_TaggedHead tests the default start when HEAD carries the release tag
+// Verifies a blank To works like "HEAD", and prefixed tags HEAD cannot reach stay out
+//
+// TestGcm_Changelog_TaggedHead 测试 HEAD 带有发布标签时的默认起点
+// 验证空 To 与 "HEAD" 的行为相同，且 HEAD 无法到达的前缀标签不参与
+func TestGcm_Changelog_TaggedHead(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-changelog-head-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	commit := func(path, message string) {
+		must.Done(os.MkdirAll(filepath.Join(tempDIR, filepath.Dir(path)), 0755))
+		must.Done(os.WriteFile(filepath.Join(tempDIR, path), []byte(message), 0644))
+		gcm.Add().Commit(message).Done()
+	}
+	commit("a.txt", "feat: initial release")
+	gcm.Tag("v1.0.0").Done()
+	commit("b.txt", "fix: handle blank names")
+	gcm.Tag("v1.0.1").Done()
+
+	blank := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{}))
+	head := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{To: "HEAD"}))
+	require.Equal(t, "v1.0.0", blank.From)
+	require.Equal(t, head.From, blank.From)
+	require.Len(t, blank.Groups, 1)
+	require.Equal(t, "handle blank names", blank.Groups[0].Entries[0].Subject)
+
+	// A higher auth version on an unmerged branch is not the start of main
+	// 未合并分支上更高的 auth 版本不是 main 的起点
+	commit("auth/a.txt", "feat(auth): add login")
+	gcm.Tag("auth/v1.0.0").Done()
+	gcm.CheckoutNewBranch("next").Done()
+	commit("auth/b.txt", "feat(auth): add tokens")
+	gcm.Tag("auth/v2.0.0").Checkout("main").Done()
+	commit("auth/c.txt", "fix(auth): expire sessions")
+
+	changelog := rese.P1(gcm.Changelog(gitgo.ChangelogOptions{TagPrefix: "auth/"}))
+	require.Equal(t, "auth/v1.0.0", changelog.From)
+	require.Len(t, changelog.Groups, 1)
+	require.Equal(t, "expire sessions", changelog.Groups[0].Entries[0].Subject)
+}
@@ -0,0 +1,94 @@
+package gitgo
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// Partial clone filters of CloneOptions.Filter
+//
+// CloneOptions.Filter 的部分克隆过滤器
+const (
+	CloneFilterBlobless = "blob:none" // Fetch blobs on demand, keep commits and trees // 按需获取 blob，保留提交和树
+	CloneFilterTreeless = "tree:0"    // Fetch trees and blobs on demand, keep commits // 按需获取树和 blob，保留提交
+)
+
+// CloneOptions tunes Clone, zero value makes a full clone checking out the remote HEAD
+// Depth needs file:// URLs to take effect on local repos, like git itself
+//
+// CloneOptions 调整 Clone 的行为，零值表示完整克隆并检出远程 HEAD
+// 与 git 本身一样，本地仓库需要 file:// URL 才能使 Depth 生效
+type CloneOptions struct {
+	Depth             int    // Shallow clone with this many commits, 0 means full history (--depth) // 浅克隆的提交数，0 表示完整历史（--depth）
+	Filter            string // Partial clone filter like CloneFilterBlobless (--filter) // 部分克隆过滤器，如 CloneFilterBlobless（--filter）
+	SingleBranch      bool   // Fetch only the checked out branch (--single-branch) // 只获取检出的分支（--single-branch）
+	Branch            string // Branch or tag to check out instead of the remote HEAD (--branch) // 代替远程 HEAD 检出的分支或标签（--branch）
+	Bare              bool   // Make a bare repo without worktree (--bare) // 创建没有工作区的裸仓库（--bare）
+	Mirror            bool   // Make a bare repo mirroring each ref of the source (--mirror) // 创建镜像源仓库每个引用的裸仓库（--mirror）
+	Reference         string // Borrow objects of this local repo as alternates (--reference) // 借用此本地仓库的对象作为 alternates（--reference）
+	RecurseSubmodules bool   // Clone submodules, nested ones included (--recurse-submodules) // 克隆子模块，包括嵌套的子模块（--recurse-submodules）
+
+	Context context.Context // Kills the clone once done, like Gcm.WithContext, nil means none // 结束时终止克隆，同 Gcm.WithContext，nil 表示不设置
+	Timeout time.Duration   // Clone deadline, like Gcm.WithTimeout, 0 means none // 克隆的截止时间，同 Gcm.WithTimeout，0 表示不设置
+	Runner  Runner          // Runs the clone, like Gcm.WithRunner, nil means os/exec // 执行克隆，同 Gcm.WithRunner，nil 表示使用 os/exec
+}
+
+// Clone clones the repo at url into dest and returns a Gcm bound to dest
+// Relative url and dest are resolved from the process working path
+// The returned Gcm keeps the Context, Timeout and Runner of opts
+// Use case: prepare CI checkouts and mirrors without shelling out before New
+//
+// Clone 将 url 处的仓库克隆到 dest 并返回绑定到 dest 的 Gcm
+// 相对的 url 和 dest 基于进程工作路径解析
+// 返回的 Gcm 保留 opts 中的 Context、Timeout 和 Runner
+// 使用场景：准备 CI 检出和镜像，无需在 New 之前调用 shell
+func Clone(url, dest string, opts CloneOptions) (*Gcm, error) {
+	destPath, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	args := []string{"clone"}
+	if opts.Depth > 0 {
+		args = append(args, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.Filter != "" {
+		args = append(args, "--filter="+opts.Filter)
+	}
+	if opts.SingleBranch {
+		args = append(args, "--single-branch")
+	}
+	if opts.Branch != "" {
+		args = append(args, "--branch", opts.Branch)
+	}
+	if opts.Bare {
+		args = append(args, "--bare")
+	}
+	if opts.Mirror {
+		args = append(args, "--mirror")
+	}
+	if opts.Reference != "" {
+		args = append(args, "--reference", opts.Reference)
+	}
+	if opts.RecurseSubmodules {
+		args = append(args, "--recurse-submodules")
+	}
+	args = append(args, "--", url, destPath)
+	base := New("")
+	if opts.Context != nil {
+		base = base.WithContext(opts.Context)
+	}
+	if opts.Timeout > 0 {
+		base = base.WithTimeout(opts.Timeout)
+	}
+	if opts.Runner != nil {
+		base = base.WithRunner(opts.Runner)
+	}
+	if err := base.do("git", args...).Reason(); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return base.newPathGcm(destPath), nil
+}
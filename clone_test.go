@@ -0,0 +1,112 @@
+package gitgo_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/go-xlan/gitgo/gitgotest"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestClone tests cloning a local bare repo with full, shallow, partial, bare, mirror and reference options
+// Verifies recursive clones check out submodules
+//
+// TestClone 测试使用完整、浅、部分、裸、镜像和 reference 选项克隆本地裸仓库
+// 验证递归克隆会检出子模块
+func TestClone(t *testing.T) {
+	// Local path submodule URLs need the file protocol since git 2.38.1 // 自 git 2.38.1 起本地路径子模块 URL 需要允许 file 协议
+	// Partial clones need the serving side to allow filters // 部分克隆需要服务端允许过滤器
+	t.Setenv("GIT_CONFIG_COUNT", "2")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	t.Setenv("GIT_CONFIG_KEY_1", "uploadpack.allowFilter")
+	t.Setenv("GIT_CONFIG_VALUE_1", "true")
+
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-clone-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	sourcePath := filepath.Join(tempDIR, "source")
+	must.Done(os.MkdirAll(sourcePath, 0755))
+
+	source := gitgo.New(sourcePath)
+	source.Init().Done()
+	for _, content := range []string{"v1", "v2", "v3"} {
+		must.Done(os.WriteFile(filepath.Join(sourcePath, "a.txt"), []byte(content), 0644))
+		source.Add().Commit(content).Done()
+	}
+	source.Tag("v1.0.0").CheckoutNewBranch("dev").Done()
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "dev.txt"), []byte("dev"), 0644))
+	source.Add().Commit("dev work").Checkout("main").Done()
+
+	barePath := filepath.Join(tempDIR, "origin.git")
+	bare := rese.P1(gitgo.Clone(sourcePath, barePath, gitgo.CloneOptions{Bare: true}))
+	require.Equal(t, "true", rese.V1(bare.ConfigGet("core.bare")))
+
+	full := rese.P1(gitgo.Clone(barePath, filepath.Join(tempDIR, "full"), gitgo.CloneOptions{}))
+	require.Equal(t, "main", rese.V1(full.GetCurrentBranch()))
+	require.Equal(t, 3, rese.V1(full.GetCommitCount()))
+
+	shallow := rese.P1(gitgo.Clone("file://"+barePath, filepath.Join(tempDIR, "shallow"), gitgo.CloneOptions{Depth: 1, SingleBranch: true, Branch: "dev"}))
+	require.Equal(t, "dev", rese.V1(shallow.GetCurrentBranch()))
+	require.Equal(t, 1, rese.V1(shallow.GetCommitCount()))
+	require.Equal(t, []string{"origin/dev"}, rese.V1(shallow.ListRemoteBranches()))
+
+	tagged := rese.P1(gitgo.Clone(barePath, filepath.Join(tempDIR, "tagged"), gitgo.CloneOptions{Branch: "v1.0.0"}))
+	require.True(t, rese.P1(tagged.GetStatus()).Branch.IsDetached())
+
+	partial := rese.P1(gitgo.Clone("file://"+barePath, filepath.Join(tempDIR, "partial"), gitgo.CloneOptions{Filter: gitgo.CloneFilterBlobless}))
+	require.Equal(t, gitgo.CloneFilterBlobless, rese.V1(partial.ConfigGet("remote.origin.partialclonefilter")))
+	require.FileExists(t, filepath.Join(tempDIR, "partial", "a.txt"))
+
+	mirror := rese.P1(gitgo.Clone(barePath, filepath.Join(tempDIR, "mirror.git"), gitgo.CloneOptions{Mirror: true}))
+	require.Equal(t, "true", rese.V1(mirror.ConfigGet("remote.origin.mirror")))
+	require.NoDirExists(t, filepath.Join(tempDIR, "mirror.git", ".git"))
+
+	rese.P1(gitgo.Clone(barePath, filepath.Join(tempDIR, "borrowed"), gitgo.CloneOptions{Reference: sourcePath}))
+	require.FileExists(t, filepath.Join(tempDIR, "borrowed", ".git", "objects", "info", "alternates"))
+
+	source.SubmoduleAdd(barePath, "libs/lib", "").Commit("add lib").Done()
+	recursive := rese.P1(gitgo.Clone(sourcePath, filepath.Join(tempDIR, "recursive"), gitgo.CloneOptions{RecurseSubmodules: true}))
+	submodules := rese.V1(recursive.ListSubmodules())
+	require.Len(t, submodules, 1)
+	require.True(t, submodules[0].Initialized)
+	require.FileExists(t, filepath.Join(tempDIR, "recursive", "libs", "lib", "a.txt"))
+
+	_, err := gitgo.Clone(filepath.Join(tempDIR, "missing"), filepath.Join(tempDIR, "nothing"), gitgo.CloneOptions{})
+	require.Error(t, err)
+}
+
+// TestClone_Options tests Clone running on the Runner and Context of the options
+// Verifies the returned Gcm keeps them, and a canceled context stops the clone
+//
+// TestClone_Options 测试 Clone 使用选项中的 Runner 和 Context 执行
+// 验证返回的 Gcm 保留它们，且已取消的上下文会停止克隆
+func TestClone_Options(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "clone", "--depth", "1", "--", "https://example.com/repo.git", "/fake/dest")
+	runner.Expect("git", "rev-parse", "--abbrev-ref", "HEAD").Stdout("main\n")
+
+	gcm := rese.P1(gitgo.Clone("https://example.com/repo.git", "/fake/dest", gitgo.CloneOptions{Depth: 1, Runner: runner, Timeout: time.Minute}))
+	require.Equal(t, "main", rese.C1(gcm.GetCurrentBranch()))
+	runner.AssertDone(t)
+
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-clone-options-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	gitgo.New(tempDIR).Init().Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := gitgo.Clone(tempDIR, filepath.Join(tempDIR, "copy"), gitgo.CloneOptions{Context: ctx})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+	require.NoDirExists(t, filepath.Join(tempDIR, "copy"))
+}
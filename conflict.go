@@ -0,0 +1,218 @@
+package gitgo
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// ConflictType tells which sides changed an unmerged path, following git status short codes
+//
+// ConflictType 表示哪些方修改了未合并路径，与 git status 短代码对应
+type ConflictType string
+
+const (
+	ConflictBothDeleted   ConflictType = "both-deleted"    // DD: deleted by both sides // DD：双方均删除
+	ConflictAddedByUs     ConflictType = "added-by-us"     // AU: added by us only // AU：仅我方添加
+	ConflictDeletedByThem ConflictType = "deleted-by-them" // UD: modified by us, deleted by them // UD：我方修改，对方删除
+	ConflictAddedByThem   ConflictType = "added-by-them"   // UA: added by them only // UA：仅对方添加
+	ConflictDeletedByUs   ConflictType = "deleted-by-us"   // DU: deleted by us, modified by them // DU：我方删除，对方修改
+	ConflictBothAdded     ConflictType = "both-added"      // AA: added by both sides // AA：双方均添加
+	ConflictBothModified  ConflictType = "both-modified"   // UU: modified by both sides // UU：双方均修改
+)
+
+// conflictTypes maps porcelain XY codes of unmerged entries to conflict types
+//
+// conflictTypes 将未合并条目的 porcelain XY 代码映射到冲突类型
+var conflictTypes = map[string]ConflictType{
+	"DD": ConflictBothDeleted,
+	"AU": ConflictAddedByUs,
+	"UD": ConflictDeletedByThem,
+	"UA": ConflictAddedByThem,
+	"DU": ConflictDeletedByUs,
+	"AA": ConflictBothAdded,
+	"UU": ConflictBothModified,
+}
+
+// Conflict is one unmerged path with the blob IDs of its index stages
+// Blank IDs mean the side has no version of the path
+//
+// Conflict 是一个未合并路径及其暂存区各阶段的 blob ID
+// ID 为空表示该方没有此路径的版本
+type Conflict struct {
+	Path     string       // Path in the worktree // 工作区中的路径
+	Type     ConflictType // Which sides changed the path // 哪些方修改了该路径
+	BaseID   string       // Stage 1 blob of the merge base // 合并基点的阶段 1 blob
+	OursID   string       // Stage 2 blob of HEAD // HEAD 的阶段 2 blob
+	TheirsID string       // Stage 3 blob of the merged commit // 被合并提交的阶段 3 blob
+}
+
+// ConflictStage numbers the index stages of unmerged paths
+//
+// ConflictStage 表示未合并路径在暂存区中的阶段编号
+type ConflictStage int
+
+const (
+	StageBase   ConflictStage = 1 // Merge base version // 合并基点版本
+	StageOurs   ConflictStage = 2 // HEAD version // HEAD 版本
+	StageTheirs ConflictStage = 3 // Merged commit version // 被合并提交版本
+)
+
+// Conflicts gets the unmerged paths of a stopped merge, rebase, cherry-pick or revert
+// Returns the conflict type and base/ours/theirs blob IDs of each path
+// Use case: inspect and resolve conflicts in code instead of aborting the merge
+//
+// Conflicts 获取停止的 merge、rebase、cherry-pick 或 revert 中的未合并路径
+// 返回每个路径的冲突类型和 base/ours/theirs blob ID
+// 使用场景：在代码中检查并解决冲突，而不是中止合并
+func (G *Gcm) Conflicts() ([]Conflict, error) {
+	status, err := G.GetStatus()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var conflicts []Conflict
+	for _, entry := range status.Entries {
+		if entry.Type != StatusUnmerged {
+			continue
+		}
+		conflictType, ok := conflictTypes[entry.XY]
+		if !ok {
+			return nil, erero.Errorf("unknown conflict code %q of %s", entry.XY, entry.Path)
+		}
+		conflicts = append(conflicts, Conflict{
+			Path:     entry.Path,
+			Type:     conflictType,
+			BaseID:   blankZeroHash(entry.StageHashes[0]),
+			OursID:   blankZeroHash(entry.StageHashes[1]),
+			TheirsID: blankZeroHash(entry.StageHashes[2]),
+		})
+	}
+	return conflicts, nil
+}
+
+// GetConflictStage gets the content of the unmerged path at the index stage
+// Fails when the side has no version of the path
+// Use case: run custom merge logic on the base, ours and theirs contents
+//
+// GetConflictStage 获取未合并路径在暂存区指定阶段的内容
+// 该方没有此路径的版本时失败
+// 使用场景：基于 base、ours 和 theirs 内容运行自定义合并逻辑
+func (G *Gcm) GetConflictStage(path string, stage ConflictStage) ([]byte, error) {
+	output, err := G.execStdout("git", "cat-file", "blob", ":"+strconv.Itoa(int(stage))+":"+path)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return output, nil
+}
+
+// GetConflictBase gets the merge base content of the unmerged path
+//
+// GetConflictBase 获取未合并路径的合并基点内容
+func (G *Gcm) GetConflictBase(path string) ([]byte, error) {
+	return G.GetConflictStage(path, StageBase)
+}
+
+// GetConflictOurs gets the HEAD content of the unmerged path
+//
+// GetConflictOurs 获取未合并路径的 HEAD 内容
+func (G *Gcm) GetConflictOurs(path string) ([]byte, error) {
+	return G.GetConflictStage(path, StageOurs)
+}
+
+// GetConflictTheirs gets the merged commit content of the unmerged path
+//
+// GetConflictTheirs 获取未合并路径的被合并提交内容
+func (G *Gcm) GetConflictTheirs(path string) ([]byte, error) {
+	return G.GetConflictStage(path, StageTheirs)
+}
+
+// ResolveOurs resolves the unmerged path with the HEAD version and stages it
+// Removes the path when HEAD has no version of it, like deleted-by-us conflicts
+// Use case: keep the release branch side of generated files
+//
+// ResolveOurs 使用 HEAD 版本解决未合并路径并暂存
+// HEAD 没有该路径的版本时（如 deleted-by-us 冲突）删除该路径
+// 使用场景：保留发布分支一侧的生成文件
+func (G *Gcm) ResolveOurs(path string) *Gcm {
+	if G.errorOnce != nil {
+		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
+	}
+	conflict, err := G.getConflict(path)
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	if conflict.OursID == "" {
+		return G.do("git", "rm", "--quiet", "--", path)
+	}
+	return G.do("git", "checkout", "--ours", "--", path).do("git", "add", "--", path)
+}
+
+// ResolveTheirs resolves the unmerged path with the merged commit version and stages it
+// Removes the path when the merged commit has no version of it, like deleted-by-them conflicts
+// Use case: take upstream versions of lock files
+//
+// ResolveTheirs 使用被合并提交的版本解决未合并路径并暂存
+// 被合并提交没有该路径的版本时（如 deleted-by-them 冲突）删除该路径
+// 使用场景：采用上游版本的锁文件
+func (G *Gcm) ResolveTheirs(path string) *Gcm {
+	if G.errorOnce != nil {
+		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
+	}
+	conflict, err := G.getConflict(path)
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	if conflict.TheirsID == "" {
+		return G.do("git", "rm", "--quiet", "--", path)
+	}
+	return G.do("git", "checkout", "--theirs", "--", path).do("git", "add", "--", path)
+}
+
+// MarkResolved stages the worktree state of the path as its resolution
+// Stages removal when the path is gone from the worktree
+// Use case: mark paths resolved after writing merged content
+//
+// MarkResolved 将路径的工作区状态暂存为其解决结果
+// 路径已从工作区删除时暂存删除操作
+// 使用场景：写入合并后的内容后将路径标记为已解决
+func (G *Gcm) MarkResolved(path string) *Gcm {
+	return G.do("git", "add", "--all", "--", path)
+}
+
+// MergeContinue concludes the merge once each conflict is resolved
+// Keeps the prepared merge message without opening an editor
+// Use case: finish merges after resolving conflicts in code
+//
+// MergeContinue 在每个冲突都解决后完成合并
+// 保留已准备的合并消息，不打开编辑器
+// 使用场景：在代码中解决冲突后完成合并
+func (G *Gcm) MergeContinue() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "merge", "--continue")
+}
+
+// getConflict finds the conflict of the path
+//
+// getConflict 查找路径对应的冲突
+func (G *Gcm) getConflict(path string) (*Conflict, error) {
+	conflicts, err := G.Conflicts()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for idx := range conflicts {
+		if conflicts[idx].Path == path {
+			return &conflicts[idx], nil
+		}
+	}
+	return nil, erero.Errorf("path %s has no conflict", path)
+}
+
+// blankZeroHash returns blank for all-zero object names, marking absent stages
+//
+// blankZeroHash 对全零对象名返回空字符串，表示不存在的阶段
+func blankZeroHash(hash string) string {
+	if strings.Trim(hash, "0") == "" {
+		return ""
+	}
+	return hash
+}
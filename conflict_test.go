@@ -0,0 +1,101 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// newConflictTestRepo creates a repo where merging feature into main stops with conflicts
+// both.txt is modified by both sides, gone.txt deleted by us, new.txt added by both sides
+//
+// newConflictTestRepo 创建将 feature 合并到 main 时因冲突停止的仓库
+// both.txt 被双方修改，gone.txt 被我方删除，new.txt 被双方添加
+func newConflictTestRepo(t *testing.T) (*gitgo.Gcm, string) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-conflict-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "both.txt"), []byte("base\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "gone.txt"), []byte("gone\n"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "both.txt"), []byte("theirs\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "gone.txt"), []byte("theirs\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "new.txt"), []byte("theirs new\n"), 0644))
+	gcm.Add().Commit("feature").Done()
+
+	gcm.Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "both.txt"), []byte("ours\n"), 0644))
+	must.Done(os.Remove(filepath.Join(tempDIR, "gone.txt")))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "new.txt"), []byte("ours new\n"), 0644))
+	gcm.Add().Commit("main").Done()
+
+	err := gcm.Merge("feature").Reason()
+	require.True(t, gitgo.IsMergeConflict(err))
+	return gcm, tempDIR
+}
+
+// TestGcm_Conflicts tests conflict types, stage blob IDs and stage contents
+//
+// TestGcm_Conflicts 测试冲突类型、阶段 blob ID 和阶段内容
+func TestGcm_Conflicts(t *testing.T) {
+	gcm, _ := newConflictTestRepo(t)
+
+	conflicts := rese.V1(gcm.Conflicts())
+	require.Len(t, conflicts, 3)
+	byPath := map[string]gitgo.Conflict{}
+	for _, conflict := range conflicts {
+		byPath[conflict.Path] = conflict
+	}
+
+	both := byPath["both.txt"]
+	require.Equal(t, gitgo.ConflictBothModified, both.Type)
+	require.NotEmpty(t, both.BaseID)
+	require.NotEmpty(t, both.OursID)
+	require.NotEmpty(t, both.TheirsID)
+	require.Equal(t, "base\n", string(rese.V1(gcm.GetConflictBase("both.txt"))))
+	require.Equal(t, "ours\n", string(rese.V1(gcm.GetConflictOurs("both.txt"))))
+	require.Equal(t, "theirs\n", string(rese.V1(gcm.GetConflictTheirs("both.txt"))))
+
+	gone := byPath["gone.txt"]
+	require.Equal(t, gitgo.ConflictDeletedByUs, gone.Type)
+	require.Empty(t, gone.OursID)
+	_, err := gcm.GetConflictOurs("gone.txt")
+	require.Error(t, err)
+
+	fresh := byPath["new.txt"]
+	require.Equal(t, gitgo.ConflictBothAdded, fresh.Type)
+	require.Empty(t, fresh.BaseID)
+	require.Equal(t, "theirs new\n", string(rese.V1(gcm.GetConflictStage("new.txt", gitgo.StageTheirs))))
+}
+
+// TestGcm_ResolveConflicts tests resolving with each side, marking resolved and continuing
+// Verifies the merge commit gets created with the chosen contents
+//
+// TestGcm_ResolveConflicts 测试使用各方版本解决、标记已解决并继续
+// 验证合并提交使用所选内容创建
+func TestGcm_ResolveConflicts(t *testing.T) {
+	gcm, tempDIR := newConflictTestRepo(t)
+
+	require.Error(t, gcm.MergeContinue().Reason())
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "both.txt"), []byte("merged\n"), 0644))
+	gcm.MarkResolved("both.txt").ResolveOurs("gone.txt").ResolveTheirs("new.txt").Done()
+	require.Empty(t, rese.V1(gcm.Conflicts()))
+	require.Error(t, gcm.ResolveOurs("both.txt").Reason())
+
+	gcm.MergeContinue().Done()
+	require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+	require.Len(t, rese.V1(gcm.Log(gitgo.LogOptions{Limit: 1}))[0].Parents, 2)
+	require.Equal(t, "merged\n", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "both.txt")))))
+	require.Equal(t, "theirs new\n", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "new.txt")))))
+	require.NoFileExists(t, filepath.Join(tempDIR, "gone.txt"))
+}
@@ -0,0 +1,426 @@
+package gitgo
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// hunkHeaderRegexp matches hunk headers like "@@ -1,3 +1,4 @@ func main()"
+//
+// hunkHeaderRegexp 匹配 hunk 头部，如 "@@ -1,3 +1,4 @@ func main()"
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// DiffStatus is the change kind of one file in a diff
+//
+// DiffStatus 是 diff 中单个文件的变更类型
+type DiffStatus string
+
+const (
+	DiffAdded    DiffStatus = "added"    // File is new // 新文件
+	DiffModified DiffStatus = "modified" // Content or mode changed // 内容或模式已更改
+	DiffDeleted  DiffStatus = "deleted"  // File is gone // 文件已删除
+	DiffRenamed  DiffStatus = "renamed"  // File moved, maybe with edits // 文件已移动，可能带有编辑
+	DiffCopied   DiffStatus = "copied"   // File copied from another, maybe with edits // 文件从另一个文件复制，可能带有编辑
+)
+
+// DiffLineKind tells whether a hunk line is kept, added or deleted
+//
+// DiffLineKind 表示 hunk 中的行是保留、新增还是删除
+type DiffLineKind string
+
+const (
+	DiffLineContext DiffLineKind = "context" // Line on both sides // 两侧都有的行
+	DiffLineAdded   DiffLineKind = "added"   // Line on the new side // 新侧的行
+	DiffLineDeleted DiffLineKind = "deleted" // Line on the old side // 旧侧的行
+)
+
+// DiffOptions selects the two sides of Diff and DiffStat
+// Zero value compares the work tree with the index, like plain "git diff"
+// Cached compares the index with HEAD, or with From when set
+// From alone compares the ref with the work tree, From and To compare two refs
+//
+// DiffOptions 选择 Diff 和 DiffStat 比较的两侧
+// 零值比较工作区与索引，与普通的 "git diff" 相同
+// Cached 比较索引与 HEAD，设置了 From 时与 From 比较
+// 只设置 From 时比较该引用与工作区，同时设置 From 和 To 时比较两个引用
+type DiffOptions struct {
+	From        string   // Old side ref // 旧侧引用
+	To          string   // New side ref, needs From // 新侧引用，需要 From
+	Cached      bool     // Use the index as the new side (--cached) // 使用索引作为新侧（--cached）
+	Paths       []string // Limit to these paths // 限制为这些路径
+	FindCopies  bool     // Detect copies besides renames (-C) // 除重命名外还检测复制（-C）
+	ContextSize int      // Context lines around changes, 0 means the git default of 3 // 变更周围的上下文行数，0 表示 git 默认的 3 行
+}
+
+// FileDiff is the diff of one file
+// Paths drop the "a/" and "b/" prefixes, OldPath equals Path unless renamed or copied
+// Files with merge conflicts come as Unmerged without hunks, Hunk cannot hold the multi-parent lines
+//
+// FileDiff 是单个文件的 diff
+// 路径去掉了 "a/" 和 "b/" 前缀，除非重命名或复制，OldPath 与 Path 相同
+// 存在合并冲突的文件标记为 Unmerged 且没有 hunk，Hunk 无法表示多父提交的行
+type FileDiff struct {
+	Path       string     // New side path, the old path on deletes // 新侧路径，删除时为旧路径
+	OldPath    string     // Old side path, the new path on adds // 旧侧路径，新增时为新路径
+	Status     DiffStatus // Change kind // 变更类型
+	Similarity int        // Similarity percent of renames and copies // 重命名和复制的相似度百分比
+	OldMode    string     // Old file mode like "100644", blank on adds // 旧文件模式，如 "100644"，新增时为空
+	NewMode    string     // New file mode like "100755", blank on deletes // 新文件模式，如 "100755"，删除时为空
+	OldHash    string     // Abbreviated old blob hash // 旧 blob 的缩写哈希
+	NewHash    string     // Abbreviated new blob hash // 新 blob 的缩写哈希
+	Binary     bool       // Binary content without hunks // 二进制内容，没有 hunk
+	Unmerged   bool       // Unresolved merge conflict, its combined diff hunks are left out // 未解决的合并冲突，其组合 diff 的 hunk 被省略
+	Hunks      []Hunk     // Changed regions // 变更区域
+}
+
+// ModeChanged checks if the file mode changed, like gaining the exec bit
+//
+// ModeChanged 检查文件模式是否更改，如获得可执行位
+func (f *FileDiff) ModeChanged() bool {
+	return f.OldMode != "" && f.NewMode != "" && f.OldMode != f.NewMode
+}
+
+// Hunk is one changed region of a file
+//
+// Hunk 是文件中的一个变更区域
+type Hunk struct {
+	OldStart int        // First old line number // 旧侧起始行号
+	OldLines int        // Old line count // 旧侧行数
+	NewStart int        // First new line number // 新侧起始行号
+	NewLines int        // New line count // 新侧行数
+	Section  string     // Text after the closing "@@", like the function name // 结尾 "@@" 之后的文本，如函数名
+	Lines    []DiffLine // Lines of the hunk // hunk 中的行
+}
+
+// DiffLine is one line of a hunk with its line numbers
+//
+// DiffLine 是 hunk 中带有行号的一行
+type DiffLine struct {
+	Kind      DiffLineKind // Context, added or deleted // 上下文、新增或删除
+	Content   string       // Text without the leading marker // 不含前导标记的文本
+	OldLine   int          // Old line number, 0 on added lines // 旧侧行号，新增行为 0
+	NewLine   int          // New line number, 0 on deleted lines // 新侧行号，删除行为 0
+	NoNewline bool         // Line misses the trailing newline // 该行缺少结尾换行符
+}
+
+// FileStat is the numstat of one file
+//
+// FileStat 是单个文件的 numstat 统计
+type FileStat struct {
+	Path       string // New side path // 新侧路径
+	OldPath    string // Old side path, equals Path unless renamed or copied // 旧侧路径，除非重命名或复制，与 Path 相同
+	Insertions int    // Added lines // 新增行数
+	Deletions  int    // Deleted lines // 删除行数
+	Binary     bool   // Binary file without line counts // 二进制文件，没有行数统计
+}
+
+// DiffStat is the numstat of each file and the totals
+//
+// DiffStat 是每个文件的 numstat 统计及总计
+type DiffStat struct {
+	Files        []FileStat // Stats of each file // 每个文件的统计
+	FilesChanged int        // Changed file count // 变更的文件数
+	Insertions   int        // Total added lines // 新增行总数
+	Deletions    int        // Total deleted lines // 删除行总数
+}
+
+// Diff gets the typed diff of each changed file with parsed hunks and line numbers
+// Renames are detected, external diff tools and textconv filters are off
+// Use case: review changes line by line instead of reading GetModifiedFiles names
+//
+// Diff 获取每个变更文件的类型化 diff，包含解析后的 hunk 和行号
+// 会检测重命名，禁用外部 diff 工具和 textconv 过滤器
+// 使用场景：逐行审查变更，而不只是读取 GetModifiedFiles 的名称
+func (G *Gcm) Diff(opts DiffOptions) ([]FileDiff, error) {
+	args, err := diffArgs(opts, "--no-color", "--no-textconv", "--src-prefix=a/", "--dst-prefix=b/")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	output, err := G.execStdout("git", args...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseDiff(output)
+}
+
+// DiffStat gets the --numstat line counts of each changed file and the totals
+// Use case: size checks in review bots
+//
+// DiffStat 获取每个变更文件的 --numstat 行数统计及总计
+// 使用场景：评审机器人中的变更规模检查
+func (G *Gcm) DiffStat(opts DiffOptions) (*DiffStat, error) {
+	args, err := diffArgs(opts, "--numstat", "-z")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	output, err := G.execStdout("git", args...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseNumstat(output)
+}
+
+// diffArgs builds the git diff args shared by Diff and DiffStat
+//
+// diffArgs 构建 Diff 和 DiffStat 共用的 git diff 参数
+func diffArgs(opts DiffOptions, extra ...string) ([]string, error) {
+	if opts.To != "" && opts.From == "" {
+		return nil, erero.New("diff to a ref needs the from ref")
+	}
+	if opts.To != "" && opts.Cached {
+		return nil, erero.New("diff between two refs cannot use the index")
+	}
+	args := append([]string{"diff", "--no-ext-diff", "--find-renames"}, extra...)
+	if opts.FindCopies {
+		args = append(args, "--find-copies")
+	}
+	if opts.ContextSize > 0 {
+		args = append(args, "--unified="+strconv.Itoa(opts.ContextSize))
+	}
+	if opts.Cached {
+		args = append(args, "--cached")
+	}
+	if opts.From != "" {
+		args = append(args, opts.From)
+	}
+	if opts.To != "" {
+		args = append(args, opts.To)
+	}
+	args = append(args, "--")
+	return append(args, opts.Paths...), nil
+}
+
+// parseDiff parses the patch output of git diff into file diffs
+// Hunk lines are counted against the header, so content like "--- x" inside hunks stays content
+// Conflicted files show up as "diff --cc" combined diffs or "* Unmerged path" lines, both become Unmerged
+//
+// parseDiff 将 git diff 的补丁输出解析为文件 diff
+// hunk 中的行按头部计数，因此 hunk 内类似 "--- x" 的内容仍作为内容处理
+// 冲突文件显示为 "diff --cc" 组合 diff 或 "* Unmerged path" 行，两者都标记为 Unmerged
+func parseDiff(output []byte) ([]FileDiff, error) {
+	var files []FileDiff
+	var file *FileDiff
+	var hunk *Hunk
+	var oldLeft, newLeft, oldLine, newLine int
+	for _, line := range strings.Split(string(output), "\n") {
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			var diffLine DiffLine
+			switch {
+			case strings.HasPrefix(line, "+"):
+				diffLine = DiffLine{Kind: DiffLineAdded, Content: line[1:], NewLine: newLine}
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				diffLine = DiffLine{Kind: DiffLineDeleted, Content: line[1:], OldLine: oldLine}
+				oldLine++
+				oldLeft--
+			case strings.HasPrefix(line, " ") || line == "":
+				diffLine = DiffLine{Kind: DiffLineContext, Content: strings.TrimPrefix(line, " "), OldLine: oldLine, NewLine: newLine}
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(line, `\`):
+				if len(hunk.Lines) > 0 {
+					hunk.Lines[len(hunk.Lines)-1].NoNewline = true
+				}
+				continue
+			default:
+				return nil, erero.Errorf("wrong hunk line %q", line)
+			}
+			hunk.Lines = append(hunk.Lines, diffLine)
+			continue
+		}
+		if strings.HasPrefix(line, `\`) && hunk != nil && len(hunk.Lines) > 0 {
+			hunk.Lines[len(hunk.Lines)-1].NoNewline = true
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "diff --git "); ok {
+			files = append(files, FileDiff{Status: DiffModified})
+			file = &files[len(files)-1]
+			hunk = nil
+			file.OldPath, file.Path = parseDiffGitPaths(rest)
+			continue
+		}
+		if path, ok := cutUnmergedDiffPath(line); ok {
+			files = append(files, FileDiff{Path: unquoteDiffPath(path), Status: DiffModified, Unmerged: true})
+			file = &files[len(files)-1]
+			hunk = nil
+			continue
+		}
+		if file != nil && file.Unmerged {
+			continue // Combined diff headers and "@@@" hunks // 组合 diff 的头部和 "@@@" hunk
+		}
+		if file == nil {
+			if line == "" {
+				continue
+			}
+			return nil, erero.Errorf("wrong diff line %q", line)
+		}
+		if matches := hunkHeaderRegexp.FindStringSubmatch(line); matches != nil {
+			file.Hunks = append(file.Hunks, Hunk{
+				OldStart: atoiOr(matches[1], 0),
+				OldLines: atoiOr(matches[2], 1),
+				NewStart: atoiOr(matches[3], 0),
+				NewLines: atoiOr(matches[4], 1),
+				Section:  matches[5],
+			})
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft, oldLine, newLine = hunk.OldLines, hunk.NewLines, hunk.OldStart, hunk.NewStart
+			continue
+		}
+		parseDiffHeaderLine(file, line)
+	}
+	for idx := range files {
+		if files[idx].OldPath == "" {
+			files[idx].OldPath = files[idx].Path
+		}
+		if files[idx].Path == "" {
+			files[idx].Path = files[idx].OldPath
+		}
+	}
+	return files, nil
+}
+
+// cutUnmergedDiffPath gets the path of "diff --cc", "diff --combined" and "* Unmerged path" lines
+// Combined hunk lines carry one marker per parent, so they never start with "diff "
+//
+// cutUnmergedDiffPath 获取 "diff --cc"、"diff --combined" 和 "* Unmerged path" 行中的路径
+// 组合 hunk 的行每个父提交带一个标记，因此不会以 "diff " 开头
+func cutUnmergedDiffPath(line string) (string, bool) {
+	for _, prefix := range []string{"diff --cc ", "diff --combined ", "* Unmerged path "} {
+		if path, ok := strings.CutPrefix(line, prefix); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// parseDiffHeaderLine applies one extended header line like "new file mode" to the file diff
+//
+// parseDiffHeaderLine 将一行扩展头部（如 "new file mode"）应用到文件 diff
+func parseDiffHeaderLine(file *FileDiff, line string) {
+	if value, ok := strings.CutPrefix(line, "new file mode "); ok {
+		file.Status, file.NewMode = DiffAdded, value
+	} else if value, ok := strings.CutPrefix(line, "deleted file mode "); ok {
+		file.Status, file.OldMode = DiffDeleted, value
+	} else if value, ok := strings.CutPrefix(line, "old mode "); ok {
+		file.OldMode = value
+	} else if value, ok := strings.CutPrefix(line, "new mode "); ok {
+		file.NewMode = value
+	} else if value, ok := strings.CutPrefix(line, "similarity index "); ok {
+		file.Similarity = atoiOr(strings.TrimSuffix(value, "%"), 0)
+	} else if value, ok := strings.CutPrefix(line, "rename from "); ok {
+		file.Status, file.OldPath = DiffRenamed, unquoteDiffPath(value)
+	} else if value, ok := strings.CutPrefix(line, "rename to "); ok {
+		file.Status, file.Path = DiffRenamed, unquoteDiffPath(value)
+	} else if value, ok := strings.CutPrefix(line, "copy from "); ok {
+		file.Status, file.OldPath = DiffCopied, unquoteDiffPath(value)
+	} else if value, ok := strings.CutPrefix(line, "copy to "); ok {
+		file.Status, file.Path = DiffCopied, unquoteDiffPath(value)
+	} else if value, ok := strings.CutPrefix(line, "index "); ok {
+		hashes, mode, _ := strings.Cut(value, " ")
+		file.OldHash, file.NewHash, _ = strings.Cut(hashes, "..")
+		if mode != "" {
+			file.OldMode, file.NewMode = mode, mode
+		}
+	} else if strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch" {
+		file.Binary = true
+	} else if value, ok := strings.CutPrefix(line, "--- "); ok && value != "/dev/null" {
+		file.OldPath = strings.TrimPrefix(unquoteDiffPath(strings.TrimSuffix(value, "\t")), "a/")
+	} else if value, ok := strings.CutPrefix(line, "+++ "); ok && value != "/dev/null" {
+		file.Path = strings.TrimPrefix(unquoteDiffPath(strings.TrimSuffix(value, "\t")), "b/")
+	}
+}
+
+// parseDiffGitPaths gets the old and new paths of a "diff --git a/x b/x" line
+// Unquoted paths with spaces are split at the middle, renames get fixed by the "rename" lines later
+//
+// parseDiffGitPaths 获取 "diff --git a/x b/x" 行中的旧路径和新路径
+// 带空格的未加引号路径从中间拆分，重命名会由之后的 "rename" 行修正
+func parseDiffGitPaths(rest string) (oldPath, newPath string) {
+	if strings.HasPrefix(rest, `"`) {
+		if first, err := strconv.QuotedPrefix(rest); err == nil {
+			oldPath, newPath = unquoteDiffPath(first), unquoteDiffPath(strings.TrimPrefix(rest[len(first):], " "))
+		}
+	} else if idx := strings.LastIndex(rest, ` "b/`); idx >= 0 && strings.HasSuffix(rest, `"`) {
+		oldPath, newPath = rest[:idx], unquoteDiffPath(rest[idx+1:])
+	} else if size := (len(rest) - 1) / 2; len(rest)%2 == 1 && rest[size] == ' ' {
+		oldPath, newPath = rest[:size], rest[size+1:]
+	}
+	return strings.TrimPrefix(oldPath, "a/"), strings.TrimPrefix(newPath, "b/")
+}
+
+// unquoteDiffPath decodes the C-style quoting git applies to paths with special chars
+//
+// unquoteDiffPath 解码 git 对带特殊字符路径使用的 C 风格引号
+func unquoteDiffPath(path string) string {
+	if strings.HasPrefix(path, `"`) {
+		if unquoted, err := strconv.Unquote(path); err == nil {
+			return unquoted
+		}
+	}
+	return path
+}
+
+// parseNumstat parses the -z --numstat output into file stats with totals
+// Renames are "added\tdeleted\t" followed by the old and new paths as two NUL ended items
+//
+// parseNumstat 将 -z --numstat 输出解析为带总计的文件统计
+// 重命名记录为 "added\tdeleted\t"，后跟以 NUL 结尾的旧路径和新路径两项
+func parseNumstat(output []byte) (*DiffStat, error) {
+	stat := &DiffStat{}
+	items := bytes.Split(output, []byte{0})
+	for idx := 0; idx < len(items); idx++ {
+		item := string(items[idx])
+		if item == "" {
+			continue
+		}
+		fields := strings.SplitN(item, "\t", 3)
+		if len(fields) != 3 {
+			return nil, erero.Errorf("wrong numstat item %q", item)
+		}
+		fileStat := FileStat{Path: fields[2], OldPath: fields[2]}
+		if fields[2] == "" {
+			if idx+2 >= len(items) {
+				return nil, erero.Errorf("wrong numstat rename item %q", item)
+			}
+			fileStat.OldPath, fileStat.Path = string(items[idx+1]), string(items[idx+2])
+			idx += 2
+		}
+		if fields[0] == "-" && fields[1] == "-" {
+			fileStat.Binary = true
+		} else {
+			var err error
+			if fileStat.Insertions, err = strconv.Atoi(fields[0]); err != nil {
+				return nil, erero.Wro(err)
+			}
+			if fileStat.Deletions, err = strconv.Atoi(fields[1]); err != nil {
+				return nil, erero.Wro(err)
+			}
+		}
+		stat.Files = append(stat.Files, fileStat)
+		stat.FilesChanged++
+		stat.Insertions += fileStat.Insertions
+		stat.Deletions += fileStat.Deletions
+	}
+	return stat, nil
+}
+
+// atoiOr converts the text to int, returning fallback when blank
+//
+// atoiOr 将文本转换为 int，为空时返回 fallback
+func atoiOr(text string, fallback int) int {
+	if text == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
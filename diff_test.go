@@ -0,0 +1,185 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Diff tests typed diffs of the work tree, the index and ref pairs
+// Verifies statuses, renames with similarity, mode changes, binary files and hunk line numbers
+//
+// TestGcm_Diff 测试工作区、索引和引用对之间的类型化 diff
+// 验证状态、带相似度的重命名、模式变更、二进制文件和 hunk 行号
+func TestGcm_Diff(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-diff-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	writeFile := func(name, content string, mode os.FileMode) {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, name), []byte(content), mode))
+		must.Done(os.Chmod(filepath.Join(tempDIR, name), mode))
+	}
+	long := strings.Repeat("stable line\n", 20)
+	writeFile("a.txt", "one\ntwo\nthree\nfour\n", 0644)
+	writeFile("old name.txt", long, 0644)
+	writeFile("gone.txt", "bye\n", 0644)
+	writeFile("run.sh", "echo hi\n", 0644)
+	writeFile("image.bin", "\x00\x01\x02", 0644)
+	gcm.Add().Commit("initial").Done()
+	require.Empty(t, rese.V1(gcm.Diff(gitgo.DiffOptions{})))
+
+	writeFile("a.txt", "one\n2\nthree\nfour\nfive", 0644)
+	diffs := rese.V1(gcm.Diff(gitgo.DiffOptions{}))
+	require.Len(t, diffs, 1)
+	file := diffs[0]
+	require.Equal(t, "a.txt", file.Path)
+	require.Equal(t, "a.txt", file.OldPath)
+	require.Equal(t, gitgo.DiffModified, file.Status)
+	require.Len(t, file.Hunks, 1)
+	hunk := file.Hunks[0]
+	require.Equal(t, 1, hunk.OldStart)
+	require.Equal(t, 4, hunk.OldLines)
+	require.Equal(t, 1, hunk.NewStart)
+	require.Equal(t, 5, hunk.NewLines)
+	require.Equal(t, []gitgo.DiffLine{
+		{Kind: gitgo.DiffLineContext, Content: "one", OldLine: 1, NewLine: 1},
+		{Kind: gitgo.DiffLineDeleted, Content: "two", OldLine: 2},
+		{Kind: gitgo.DiffLineAdded, Content: "2", NewLine: 2},
+		{Kind: gitgo.DiffLineContext, Content: "three", OldLine: 3, NewLine: 3},
+		{Kind: gitgo.DiffLineContext, Content: "four", OldLine: 4, NewLine: 4},
+		{Kind: gitgo.DiffLineAdded, Content: "five", NewLine: 5, NoNewline: true},
+	}, hunk.Lines)
+	require.Empty(t, rese.V1(gcm.Diff(gitgo.DiffOptions{Cached: true})))
+
+	must.Done(os.Rename(filepath.Join(tempDIR, "old name.txt"), filepath.Join(tempDIR, "new name.txt")))
+	writeFile("new name.txt", long+"extra line\n", 0644)
+	must.Done(os.Remove(filepath.Join(tempDIR, "gone.txt")))
+	writeFile("run.sh", "echo hi\n", 0755)
+	writeFile("image.bin", "\x00\x03\x04\x05", 0644)
+	writeFile("added.txt", "-- dashes\n--- triple\n", 0644)
+	writeFile("ü \"quoted\".txt", "quoted\n", 0644)
+	gcm.Add().Done()
+	require.Empty(t, rese.V1(gcm.Diff(gitgo.DiffOptions{})))
+
+	byPath := map[string]*gitgo.FileDiff{}
+	diffs = rese.V1(gcm.Diff(gitgo.DiffOptions{Cached: true}))
+	for idx := range diffs {
+		byPath[diffs[idx].Path] = &diffs[idx]
+	}
+	require.Len(t, byPath, 7)
+	require.Equal(t, gitgo.DiffAdded, byPath["ü \"quoted\".txt"].Status)
+
+	renamed := byPath["new name.txt"]
+	require.Equal(t, gitgo.DiffRenamed, renamed.Status)
+	require.Equal(t, "old name.txt", renamed.OldPath)
+	require.Greater(t, renamed.Similarity, 50)
+	require.Len(t, renamed.Hunks, 1)
+	require.Equal(t, gitgo.DiffLine{Kind: gitgo.DiffLineAdded, Content: "extra line", NewLine: 21}, renamed.Hunks[0].Lines[len(renamed.Hunks[0].Lines)-1])
+
+	require.Equal(t, gitgo.DiffDeleted, byPath["gone.txt"].Status)
+	require.Equal(t, "100644", byPath["gone.txt"].OldMode)
+	require.Empty(t, byPath["gone.txt"].NewMode)
+
+	script := byPath["run.sh"]
+	require.Equal(t, gitgo.DiffModified, script.Status)
+	require.True(t, script.ModeChanged())
+	require.Equal(t, "100644", script.OldMode)
+	require.Equal(t, "100755", script.NewMode)
+	require.Empty(t, script.Hunks)
+
+	require.True(t, byPath["image.bin"].Binary)
+	require.Empty(t, byPath["image.bin"].Hunks)
+
+	added := byPath["added.txt"]
+	require.Equal(t, gitgo.DiffAdded, added.Status)
+	require.Equal(t, "100644", added.NewMode)
+	require.Equal(t, []string{"-- dashes", "--- triple"}, []string{added.Hunks[0].Lines[0].Content, added.Hunks[0].Lines[1].Content})
+	require.False(t, added.ModeChanged())
+
+	gcm.Commit("second").Done()
+	diffs = rese.V1(gcm.Diff(gitgo.DiffOptions{From: "HEAD~1", To: "HEAD", Paths: []string{"a.txt"}}))
+	require.Len(t, diffs, 1)
+	require.Equal(t, "a.txt", diffs[0].Path)
+	require.Len(t, rese.V1(gcm.Diff(gitgo.DiffOptions{From: "HEAD~1"})), 7)
+
+	_, err := gcm.Diff(gitgo.DiffOptions{To: "HEAD"})
+	require.Error(t, err)
+	_, err = gcm.Diff(gitgo.DiffOptions{From: "missing", To: "HEAD"})
+	require.Error(t, err)
+}
+
+// TestGcm_Diff_Conflict tests diffs of a merge stopped by conflicts
+// Verifies conflicted files come as Unmerged without hunks, next to plain file diffs
+//
+// TestGcm_Diff_Conflict 测试因冲突停止的合并的 diff
+// 验证冲突文件标记为 Unmerged 且没有 hunk，且与普通文件 diff 共存
+func TestGcm_Diff_Conflict(t *testing.T) {
+	gcm, tempDIR := newConflictTestRepo(t)
+
+	paths := func(diffs []gitgo.FileDiff) []string {
+		var names []string
+		for _, diff := range diffs {
+			require.True(t, diff.Unmerged)
+			require.Empty(t, diff.Hunks)
+			require.Equal(t, diff.Path, diff.OldPath)
+			names = append(names, diff.Path)
+		}
+		return names
+	}
+	// Content conflicts come as "diff --cc", the delete conflict as "* Unmerged path"
+	// 内容冲突显示为 "diff --cc"，删除冲突显示为 "* Unmerged path"
+	require.ElementsMatch(t, []string{"both.txt", "new.txt", "gone.txt"}, paths(rese.V1(gcm.Diff(gitgo.DiffOptions{}))))
+	require.ElementsMatch(t, []string{"both.txt", "new.txt", "gone.txt"}, paths(rese.V1(gcm.Diff(gitgo.DiffOptions{Cached: true}))))
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "both.txt"), []byte("ours\ntheirs\n"), 0644))
+	gcm.Add().Done()
+	diffs := rese.V1(gcm.Diff(gitgo.DiffOptions{Cached: true, Paths: []string{"both.txt"}}))
+	require.Len(t, diffs, 1)
+	require.False(t, diffs[0].Unmerged)
+	require.Len(t, diffs[0].Hunks, 1)
+	require.Equal(t, "theirs", diffs[0].Hunks[0].Lines[1].Content)
+}
+
+// TestGcm_DiffStat tests numstat line counts, renames, binary files and totals
+//
+// TestGcm_DiffStat 测试 numstat 行数、重命名、二进制文件和总计
+func TestGcm_DiffStat(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-diffstat-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	long := strings.Repeat("stable line\n", 20)
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("one\ntwo\n"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "old.txt"), []byte(long), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "image.bin"), []byte("\x00\x01"), 0644))
+	gcm.Add().Commit("initial").Done()
+	require.Equal(t, &gitgo.DiffStat{}, rese.P1(gcm.DiffStat(gitgo.DiffOptions{})))
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("one\n2\n3\n"), 0644))
+	must.Done(os.Rename(filepath.Join(tempDIR, "old.txt"), filepath.Join(tempDIR, "new.txt")))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "image.bin"), []byte("\x00\x02"), 0644))
+	gcm.Add().Commit("second").Done()
+
+	stat := rese.P1(gcm.DiffStat(gitgo.DiffOptions{From: "HEAD~1", To: "HEAD"}))
+	require.Equal(t, 3, stat.FilesChanged)
+	require.Equal(t, 2, stat.Insertions)
+	require.Equal(t, 1, stat.Deletions)
+	require.ElementsMatch(t, []gitgo.FileStat{
+		{Path: "a.txt", OldPath: "a.txt", Insertions: 2, Deletions: 1},
+		{Path: "image.bin", OldPath: "image.bin", Binary: true},
+		{Path: "new.txt", OldPath: "old.txt"},
+	}, stat.Files)
+}
@@ -0,0 +1,137 @@
+package gitgo
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// readOnlySubcommands lists git subcommands that never change the repo
+//
+// readOnlySubcommands 列出永不修改仓库的 git 子命令
+var readOnlySubcommands = []string{
+	"blame", "cat-file", "check-ignore", "check-ref-format", "cherry", "count-objects",
+	"describe", "diff", "diff-files", "diff-index", "diff-tree", "for-each-ref", "grep",
+	"help", "log", "ls-files", "ls-remote", "ls-tree", "merge-base", "name-rev",
+	"range-diff", "rev-list", "rev-parse", "shortlog", "show", "show-ref", "status",
+	"var", "version", "whatchanged",
+}
+
+// readOnlyFilters lists shell programs that only filter the piped git output
+//
+// readOnlyFilters 列出仅过滤管道中 git 输出的 shell 程序
+var readOnlyFilters = []string{"cut", "grep", "head", "sort", "tail", "uniq", "wc"}
+
+// isReadOnlyCommand tells whether the command only reads the repo, so dry-run still runs it
+// Unknown commands count as mutating, shell lines must be pipes of read-only git and filters
+//
+// isReadOnlyCommand 判断命令是否只读取仓库，dry-run 时仍会运行此类命令
+// 未知命令视为修改性命令，shell 行必须是只读 git 和过滤程序组成的管道
+func isReadOnlyCommand(command *Command) bool {
+	if command.ShellType == "" {
+		return isReadOnlyGit(command.Argv())
+	}
+	line := strings.Join(command.Argv(), " ")
+	if strings.ContainsAny(line, ";&<>`$\n") {
+		return false
+	}
+	for _, segment := range strings.Split(line, "|") {
+		argv := strings.Fields(segment)
+		if len(argv) == 0 {
+			return false
+		}
+		if !slices.Contains(readOnlyFilters, argv[0]) && !isReadOnlyGit(argv) {
+			return false
+		}
+	}
+	return true
+}
+
+// isReadOnlyGit tells whether the git argv only reads the repo
+// Listing forms of branch, tag, remote, config and stash count as read-only
+//
+// isReadOnlyGit 判断 git argv 是否只读取仓库
+// branch、tag、remote、config 和 stash 的列表形式视为只读
+func isReadOnlyGit(argv []string) bool {
+	subcommand, args, ok := splitGitArgs(argv)
+	if !ok {
+		return false
+	}
+	if subcommand == "" {
+		return true
+	}
+	if slices.Contains(readOnlySubcommands, subcommand) {
+		return true
+	}
+	switch subcommand {
+	case "branch":
+		return isListing(args, 0,
+			[]string{"-l", "--list", "-a", "--all", "-r", "--remotes", "--show-current", "--merged", "--no-merged", "--contains", "--no-contains", "--points-at"},
+			[]string{"-d", "-D", "--delete", "-m", "-M", "--move", "-c", "-C", "--copy", "-f", "--force", "-u", "--set-upstream-to", "--unset-upstream", "--edit-description"})
+	case "tag":
+		return isListing(args, 0,
+			[]string{"-l", "--list", "--merged", "--no-merged", "--contains", "--no-contains", "--points-at"},
+			[]string{"-d", "--delete", "-a", "--annotate", "-s", "--sign", "-u", "--local-user", "-f", "--force", "-m", "--message", "-F", "--file"})
+	case "config":
+		return isListing(args, 1,
+			[]string{"--get", "--get-all", "--get-regexp", "--get-urlmatch", "-l", "--list"},
+			[]string{"--add", "--unset", "--unset-all", "--replace-all", "--rename-section", "--remove-section", "-e", "--edit"})
+	case "remote":
+		return len(args) == 0 || slices.Contains([]string{"-v", "--verbose", "get-url", "show"}, args[0])
+	case "stash", "worktree", "notes":
+		return len(args) > 0 && slices.Contains([]string{"list", "show"}, args[0])
+	case "submodule":
+		return len(args) > 0 && slices.Contains([]string{"status", "summary"}, args[0])
+	default:
+		return false
+	}
+}
+
+// gitValueOptions are global options taking their value as the next arg, like "--git-dir .git"
+//
+// gitValueOptions 是将下一个参数作为值的全局选项，如 "--git-dir .git"
+var gitValueOptions = []string{"-C", "-c", "--git-dir", "--work-tree", "--namespace", "--exec-path", "--super-prefix"}
+
+// splitGitArgs splits a git argv into the subcommand and its args
+// Skips global options like -C <path>, -c <key=value> and --git-dir <path>, ok is false when argv is not git
+//
+// splitGitArgs 将 git argv 拆分为子命令及其参数
+// 跳过 -C <path>、-c <key=value> 和 --git-dir <path> 等全局选项，argv 不是 git 时 ok 为 false
+func splitGitArgs(argv []string) (subcommand string, args []string, ok bool) {
+	if len(argv) == 0 || filepath.Base(argv[0]) != "git" {
+		return "", nil, false
+	}
+	args = argv[1:]
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if slices.Contains(gitValueOptions, args[0]) && len(args) > 1 {
+			args = args[1:]
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return "", nil, true
+	}
+	return args[0], args[1:], true
+}
+
+// isListing tells whether args select the listing form of a subcommand
+// Writing flags win, listing flags settle it, else positional args must not exceed maxTargets
+//
+// isListing 判断参数是否选择了子命令的列表形式
+// 写入标志优先，列表标志直接确定，否则位置参数不能超过 maxTargets
+func isListing(args []string, maxTargets int, listFlags []string, writeFlags []string) bool {
+	var targets int
+	var listing bool
+	for _, arg := range args {
+		flag, _, _ := strings.Cut(arg, "=")
+		switch {
+		case slices.Contains(writeFlags, flag):
+			return false
+		case slices.Contains(listFlags, flag):
+			listing = true
+		case !strings.HasPrefix(arg, "-"):
+			targets++
+		}
+	}
+	return listing || targets <= maxTargets
+}
@@ -0,0 +1,51 @@
+package gitgo_test
+
+import (
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/go-xlan/gitgo/gitgotest"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_WithDryRun tests mutating commands getting skipped while queries still run
+// Verifies listing forms of tag stay read-only, sorted tag queries included
+//
+// TestGcm_WithDryRun 测试修改性命令被跳过而查询仍会运行
+// 验证 tag 的列表形式保持只读，包括排序的标签查询
+func TestGcm_WithDryRun(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "tag", "--list").Stdout("v1.0.0\n")
+	runner.Expect("git", "rev-parse", "--abbrev-ref", "HEAD").Stdout("main\n")
+	runner.Expect("git", "tag", "--list", "--").Stdout("v1.0.0\nauth/v2.0.0\n")
+	runner.Expect("git", "tag", "--list", "--sort=-creatordate", "--").Stdout("v1.0.0\n")
+
+	recorder := gitgo.NewRecorder()
+	gcm := gitgo.New("/fake/repo").WithRunner(runner).WithDryRun().WithRecorder(recorder)
+
+	output := gcm.Add().Commit("release").Tags().Nice()
+	require.Equal(t, "v1.0.0\n", string(output))
+	gcm.Tag("v1.0.1").ResetHard().Push().PushTags().Done()
+	require.Equal(t, "main", rese.C1(gcm.GetCurrentBranch()))
+	require.Equal(t, "v1.0.0", rese.C1(gcm.GetLatestTagHasPrefix("v")))
+	require.Equal(t, []string{"v1.0.0"}, rese.V1(gcm.ListTags(gitgo.TagQuery{Sort: []string{"-creatordate"}})))
+	runner.AssertDone(t)
+
+	var skipped [][]string
+	for _, record := range recorder.Records() {
+		require.Equal(t, "/fake/repo", record.Path)
+		require.Equal(t, record.DryRun, !record.ReadOnly)
+		if record.DryRun {
+			skipped = append(skipped, record.Args)
+		}
+	}
+	require.Equal(t, [][]string{
+		{"git", "add", "."},
+		{"git", "commit", "-m", "release"},
+		{"git", "tag", "v1.0.1"},
+		{"git", "reset", "--hard"},
+		{"git", "push"},
+		{"git", "push", "--tags"},
+	}, skipped)
+}
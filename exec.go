@@ -0,0 +1,181 @@
+package gitgo
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/yyle88/osexec"
+	"github.com/yyle88/zaplog"
+)
+
+// exec runs the command with the chain context and returns combined output
+// Shorthand of execTake on the shared execution configuration
+//
+// exec 使用链上下文运行命令并返回合并输出
+// 基于共享执行配置的 execTake 简写
+func (G *Gcm) exec(name string, args ...string) ([]byte, error) {
+	output, _, err := G.execTake(G.execConfig, name, args...)
+	return output, err
+}
+
+// execTake runs the command with the chain context and returns combined output and exit code
+// Exit codes registered in cfg.TakeExits are treated as success, like osexec ExecTake
+//
+// execTake 使用链上下文运行命令并返回合并输出和退出码
+// cfg.TakeExits 中登记的退出码视为成功，与 osexec ExecTake 一致
+func (G *Gcm) execTake(cfg *osexec.ExecConfig, name string, args ...string) ([]byte, int, error) {
+	result, err := G.run(cfg, name, args)
+	return result.Output, result.ExitCode, err
+}
+
+// execStdout runs the command with the chain context and returns stdout alone
+// Used by parsers of machine-readable output, where stderr warnings must not mix in
+//
+// execStdout 使用链上下文运行命令并仅返回 stdout
+// 供机器可读输出的解析器使用，避免 stderr 警告混入
+func (G *Gcm) execStdout(name string, args ...string) ([]byte, error) {
+	result, err := G.run(G.execConfig, name, args)
+	return result.Stdout, err
+}
+
+// run executes the command through the Runner with the chain context
+// Failures come back as *GitError, wrapping ctx.Err() when the context is done
+// The result is never nil, so callers can read output even on failures
+//
+// run 使用链上下文通过 Runner 执行命令
+// 失败时返回 *GitError，上下文结束时包装 ctx.Err()
+// 结果永不为 nil，因此调用方在失败时也能读取输出
+func (G *Gcm) run(cfg *osexec.ExecConfig, name string, args []string) (*Result, error) {
+	ctx, cancel := G.newCommandContext()
+	defer cancel()
+
+	command := newCommand(cfg, name, args)
+	if err := ctx.Err(); err != nil {
+		return &Result{ExitCode: -1}, newGitError(command, &Result{ExitCode: -1}, 0, err)
+	}
+	if cfg.IsShowCommand() {
+		zaplog.SUG.Debugln("EXEC:", command.String())
+	}
+	startTime := time.Now()
+	result, err := G.runCommand(ctx, command)
+	if err == nil {
+		return result, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return result, newGitError(command, result, time.Since(startTime), ctxErr)
+	}
+	if _, ok := cfg.TakeExits[result.ExitCode]; ok && result.ExitCode > 0 {
+		return result, nil
+	}
+	return result, newGitError(command, result, time.Since(startTime), err)
+}
+
+// runCommand hands the command to the middlewares wrapping dispatch
+// The result is never nil, like run
+//
+// runCommand 将命令交给包装 dispatch 的中间件
+// 与 run 一样，结果永不为 nil
+func (G *Gcm) runCommand(ctx context.Context, command *Command) (*Result, error) {
+	var runner Runner = RunnerFunc(G.dispatch)
+	for _, middleware := range slices.Backward(G.options.middlewares) {
+		runner = middleware(runner)
+	}
+	result, err := runner.Run(ctx, command)
+	if result == nil {
+		result = &Result{ExitCode: -1}
+	}
+	return result, err
+}
+
+// dispatch hands the command to the Runner, honoring dry-run and recorder settings
+// Dry-run skips mutating commands with a blank successful result, read-only ones still run
+//
+// dispatch 将命令交给 Runner，遵循 dry-run 和记录器设置
+// dry-run 以空的成功结果跳过修改性命令，只读命令仍会运行
+func (G *Gcm) dispatch(ctx context.Context, command *Command) (*Result, error) {
+	readOnly := isReadOnlyCommand(command)
+	dryRun := G.options.dryRun && !readOnly
+
+	startTime := time.Now()
+	var result *Result
+	var err error
+	if dryRun {
+		zaplog.SUG.Debugln("DRY-RUN:", command.String())
+		result = &Result{ExitCode: 0}
+	} else {
+		result, err = G.getRunner().Run(ctx, command)
+		if result == nil {
+			result = &Result{ExitCode: -1}
+		}
+	}
+	if G.options.recorder != nil {
+		G.options.recorder.record(command, readOnly, dryRun, result.ExitCode, time.Since(startTime))
+	}
+	return result, err
+}
+
+// getRunner returns the configured Runner, the os/exec one when unset
+//
+// getRunner 返回配置的 Runner，未设置时返回 os/exec 实现
+func (G *Gcm) getRunner() Runner {
+	if G.options.runner != nil {
+		return G.options.runner
+	}
+	return NewExecRunner()
+}
+
+// newCommandContext derives the context of one command from the chain settings
+// Applies the per-command timeout on top of the chain context when configured
+//
+// newCommandContext 根据链设置派生单个命令的上下文
+// 配置了超时时在链上下文之上应用单命令超时
+func (G *Gcm) newCommandContext() (context.Context, context.CancelFunc) {
+	ctx := G.options.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if G.options.timeout > 0 {
+		return context.WithTimeout(ctx, G.options.timeout)
+	}
+	return ctx, func() {}
+}
+
+// newCommand builds the Runner command from the execution configuration
+//
+// newCommand 根据执行配置构建 Runner 命令
+func newCommand(cfg *osexec.ExecConfig, name string, args []string) *Command {
+	return &Command{
+		Name:      name,
+		Args:      slices.Clone(args),
+		Path:      cfg.Path,
+		Envs:      slices.Clone(cfg.Envs),
+		ShellType: cfg.ShellType,
+		ShellFlag: cfg.ShellFlag,
+	}
+}
+
+// newGitError builds the GitError of a failed command from its result
+//
+// newGitError 根据失败命令的结果构建 GitError
+func newGitError(command *Command, result *Result, duration time.Duration, cause error) *GitError {
+	return &GitError{
+		Args:     command.Argv(),
+		Path:     command.Path,
+		ExitCode: result.ExitCode,
+		Stdout:   result.Stdout,
+		Stderr:   result.Stderr,
+		Duration: duration,
+		Err:      cause,
+	}
+}
+
+// newEditorlessConfig clones the execution configuration with GIT_EDITOR=true
+// Commands like rebase --continue then keep prepared messages instead of waiting on an editor
+//
+// newEditorlessConfig 克隆执行配置并设置 GIT_EDITOR=true
+// rebase --continue 等命令因此保留已准备的消息，而不会等待编辑器
+func (G *Gcm) newEditorlessConfig() *osexec.ExecConfig {
+	cfg := G.execConfig.NewConfig()
+	return cfg.WithEnvs(append(cfg.Envs, "GIT_EDITOR=true"))
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package gitgo
+
+import "os/exec"
+
+// setProcessGroupCancel keeps the default cancel behavior (kill the process) on non-unix systems
+//
+// setProcessGroupCancel 在非 unix 系统上保持默认取消行为（终止进程）
+func setProcessGroupCancel(command *exec.Cmd) {
+	_ = command
+}
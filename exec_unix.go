@@ -0,0 +1,20 @@
+//go:build unix
+
+package gitgo
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroupCancel starts the command in a new process group and kills the group on cancel
+// Ensures helpers spawned by git (ssh, credential helpers, hooks) exit with it
+//
+// setProcessGroupCancel 在新进程组中启动命令并在取消时终止整个进程组
+// 确保 git 派生的辅助进程（ssh、凭据助手、钩子）随之退出
+func setProcessGroupCancel(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	command.Cancel = func() error {
+		return syscall.Kill(-command.Process.Pid, syscall.SIGKILL)
+	}
+}
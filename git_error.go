@@ -0,0 +1,131 @@
+package gitgo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// GitError describes a failed git invocation with its complete execution details
+// Returned (wrapped) from Gcm chains and query helpers, use errors.As to extract it
+// Unwrap gives the cause, so errors.Is works with context.DeadlineExceeded and exec.ExitError
+//
+// GitError 描述一次失败的 git 调用及其完整执行详情
+// 从 Gcm 链和查询辅助函数返回（可能被包装），使用 errors.As 提取
+// Unwrap 返回原因，因此 errors.Is 可匹配 context.DeadlineExceeded 和 exec.ExitError
+type GitError struct {
+	Args     []string      // Command argv including program name // 包含程序名的命令参数
+	Path     string        // Working path of the command // 命令的工作路径
+	ExitCode int           // Exit code, -1 when not exited normally // 退出码，非正常退出时为 -1
+	Stdout   []byte        // Standard output bytes // 标准输出字节
+	Stderr   []byte        // Standard error bytes // 标准错误字节
+	Duration time.Duration // Time spent running the command // 命令运行耗时
+	Err      error         // Underlying cause // 底层原因
+}
+
+// Error renders the command, exit code and first stderr line
+//
+// Error 渲染命令、退出码和 stderr 首行
+func (e *GitError) Error() string {
+	message := fmt.Sprintf("%s: exit code %d", strings.Join(e.Args, " "), e.ExitCode)
+	if line := firstLine(e.Stderr); line != "" {
+		message += ": " + line
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+// Unwrap returns the underlying cause
+//
+// Unwrap 返回底层原因
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// AsGitError extracts the *GitError from err chain
+// Returns nil and false when err does not hold a GitError
+//
+// AsGitError 从错误链中提取 *GitError
+// 当 err 不包含 GitError 时返回 nil 和 false
+func AsGitError(err error) (*GitError, bool) {
+	var gitError *GitError
+	if errors.As(err, &gitError) {
+		return gitError, true
+	}
+	return nil, false
+}
+
+// IsNotARepository checks if err comes from running git outside a repo
+//
+// IsNotARepository 检查 err 是否来自在仓库外运行 git
+func IsNotARepository(err error) bool {
+	return matchGitError(err, "not a git repository")
+}
+
+// IsMergeConflict checks if err comes from a merge (or rebase, cherry-pick) stopping on conflicts
+//
+// IsMergeConflict 检查 err 是否来自因冲突停止的合并（或变基、拣选）
+func IsMergeConflict(err error) bool {
+	return matchGitError(err, "conflict (", "automatic merge failed", "fix conflicts", "could not apply", "unmerged files", "you have not concluded your merge")
+}
+
+// IsNonFastForward checks if err comes from a push or pull rejected as non-fast-forward
+//
+// IsNonFastForward 检查 err 是否来自因非快进而被拒绝的推送或拉取
+func IsNonFastForward(err error) bool {
+	return matchGitError(err, "non-fast-forward", "fetch first", "not possible to fast-forward", "updates were rejected")
+}
+
+// IsAuthFailure checks if err comes from failed authentication with the remote
+//
+// IsAuthFailure 检查 err 是否来自与远程的认证失败
+func IsAuthFailure(err error) bool {
+	return matchGitError(err, "authentication failed", "could not read username", "could not read password", "permission denied (publickey", "terminal prompts disabled", "invalid username or password", "the requested url returned error: 403", "the requested url returned error: 401")
+}
+
+// IsLockContention checks if err comes from another git process holding a lock file
+// Matches "unable to create '<path>.lock': File exists" only, other "unable to create" failures
+// like temp files, packs and threads are not lock contention
+//
+// IsLockContention 检查 err 是否来自其他 git 进程持有锁文件
+// 只匹配 "unable to create '<path>.lock': File exists"，临时文件、pack 和线程等
+// 其他 "unable to create" 失败不属于锁竞争
+func IsLockContention(err error) bool {
+	return matchGitError(err, ".lock': file exists", "cannot lock ref", "another git process seems to be running")
+}
+
+// matchGitError checks if the GitError output contains one of the lowercase patterns
+// Matches on git English messages, other locales need LC_ALL=C in the env settings
+//
+// matchGitError 检查 GitError 输出是否包含任一小写模式
+// 基于 git 英文消息匹配，其他语言环境需在环境变量设置中加入 LC_ALL=C
+func matchGitError(err error, patterns ...string) bool {
+	gitError, ok := AsGitError(err)
+	if !ok {
+		return false
+	}
+	message := strings.ToLower(string(gitError.Stderr) + "\n" + string(gitError.Stdout))
+	for _, pattern := range patterns {
+		if strings.Contains(message, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstLine returns the first non-blank line of the output
+//
+// firstLine 返回输出中第一个非空行
+func firstLine(output []byte) string {
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return string(line)
+		}
+	}
+	return ""
+}
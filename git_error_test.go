@@ -0,0 +1,158 @@
+package gitgo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/osexec"
+	"github.com/yyle88/rese"
+)
+
+// TestGitError_NotARepository tests GitError details when running outside a repo
+// Verifies exit code, separated stderr and classification through wrapped errors
+//
+// TestGitError_NotARepository 测试在仓库外运行时的 GitError 详情
+// 验证退出码、分离的 stderr 以及穿透包装错误的分类
+func TestGitError_NotARepository(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-no-repo-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+
+	_, err := gcm.GetCurrentBranch()
+	require.Error(t, err)
+	require.True(t, gitgo.IsNotARepository(err))
+	require.False(t, gitgo.IsMergeConflict(err))
+
+	gitError, ok := gitgo.AsGitError(err)
+	require.True(t, ok)
+	require.Equal(t, 128, gitError.ExitCode)
+	require.Equal(t, tempDIR, gitError.Path)
+	require.Equal(t, []string{"git", "rev-parse", "--abbrev-ref", "HEAD"}, gitError.Args)
+	require.Contains(t, string(gitError.Stderr), "not a git repository")
+	require.Empty(t, gitError.Stdout)
+
+	require.True(t, gitgo.IsNotARepository(gcm.Status().Reason()))
+}
+
+// TestGitError_MergeConflict tests IsMergeConflict on a conflicting merge
+// Verifies the chain error gets classified as merge conflict
+//
+// TestGitError_MergeConflict 测试冲突合并上的 IsMergeConflict
+// 验证链错误被分类为合并冲突
+func TestGitError_MergeConflict(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-conflict-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("base"), 0644))
+	gcm.Add().Commit("base").Done()
+
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("feature"), 0644))
+	gcm.Add().Commit("feature").Done()
+
+	gcm.Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("main"), 0644))
+	gcm.Add().Commit("main").Done()
+
+	err := gcm.Merge("feature").Reason()
+	require.Error(t, err)
+	require.True(t, gitgo.IsMergeConflict(err))
+	require.False(t, gitgo.IsNotARepository(err))
+}
+
+// TestGitError_NonFastForward tests IsNonFastForward on a rejected push
+// Verifies push of diverged history into a bare repo gets classified
+//
+// TestGitError_NonFastForward 测试被拒绝推送上的 IsNonFastForward
+// 验证向裸仓库推送分叉历史时被正确分类
+func TestGitError_NonFastForward(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-non-ff-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	barePath := filepath.Join(tempDIR, "remote.git")
+	must.Done(os.MkdirAll(barePath, 0755))
+	rese.V1(osexec.ExecInPath(barePath, "git", "init", "--bare"))
+
+	for idx, name := range []string{"a", "b"} {
+		path := filepath.Join(tempDIR, name)
+		must.Done(os.MkdirAll(path, 0755))
+		gcm := gitgo.New(path)
+		gcm.Init().RemoteAdd("origin", barePath).Done()
+		must.Done(os.WriteFile(filepath.Join(path, name+".txt"), []byte(name), 0644))
+		gcm.Add().Commit(name).Done()
+
+		err := gcm.PushTo("origin", "main").Reason()
+		if idx == 0 {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+			require.True(t, gitgo.IsNonFastForward(err))
+		}
+	}
+}
+
+// TestGitError_LockContention tests IsLockContention with a stale index.lock
+// Verifies Add fails and gets classified as lock contention
+//
+// TestGitError_LockContention 测试存在陈旧 index.lock 时的 IsLockContention
+// 验证 Add 失败并被分类为锁竞争
+func TestGitError_LockContention(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-lock-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, ".git", "index.lock"), []byte{}, 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("v1"), 0644))
+
+	err := gcm.Add().Reason()
+	require.Error(t, err)
+	require.True(t, gitgo.IsLockContention(err))
+}
+
+// TestGitError_LockContention_Unrelated tests IsLockContention skips "unable to create" failures without lock files
+//
+// TestGitError_LockContention_Unrelated 测试 IsLockContention 不匹配与锁文件无关的 "unable to create" 失败
+func TestGitError_LockContention_Unrelated(t *testing.T) {
+	for _, stderr := range []string{
+		"fatal: unable to create temporary file: No space left on device",
+		"fatal: unable to create '/repo/.git/objects/pack/tmp_pack_Xy12': Permission denied",
+		"fatal: unable to create thread: Resource temporarily unavailable",
+	} {
+		err := &gitgo.GitError{Args: []string{"git", "fetch"}, ExitCode: 128, Stderr: []byte(stderr + "\n")}
+		require.False(t, gitgo.IsLockContention(err), stderr)
+	}
+	err := &gitgo.GitError{
+		Args:     []string{"git", "update-ref", "refs/heads/main", "HEAD"},
+		ExitCode: 128,
+		Stderr:   []byte("fatal: Unable to create '/repo/.git/refs/heads/main.lock': File exists.\n"),
+	}
+	require.True(t, gitgo.IsLockContention(err))
+}
+
+// TestGitError_AuthFailure tests IsAuthFailure classification on captured stderr
+// Verifies matching on typical credential failure messages
+//
+// TestGitError_AuthFailure 测试基于捕获 stderr 的 IsAuthFailure 分类
+// 验证对典型凭据失败消息的匹配
+func TestGitError_AuthFailure(t *testing.T) {
+	err := &gitgo.GitError{
+		Args:     []string{"git", "push"},
+		ExitCode: 128,
+		Stderr:   []byte("fatal: could not read Username for 'https://example.com': terminal prompts disabled\n"),
+	}
+	require.True(t, gitgo.IsAuthFailure(err))
+	require.True(t, gitgo.IsAuthFailure(errors.Join(errors.New("push"), err)))
+	require.False(t, gitgo.IsAuthFailure(errors.New("fatal: could not read Username")))
+	require.Contains(t, err.Error(), "git push: exit code 128: fatal: could not read Username")
+}
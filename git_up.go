@@ -3,6 +3,7 @@ package gitgo
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,7 +19,7 @@ import (
 // 在找到暂存更改时返回 true，否则返回 false
 // 使用场景：避免在没有暂存更改时执行 commit 操作，防止问题和产生空提交
 func (G *Gcm) HasStagedChanges() (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES").ExecTake("git", "diff-index", "--cached", "--quiet", "HEAD")
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES"), "git", "diff-index", "--cached", "--quiet", "HEAD")
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -40,7 +41,7 @@ func (G *Gcm) HasStagedChanges() (bool, error) {
 // 在检测到未暂存更改时返回 true，如果工作树与暂存区匹配则返回 false
 // 使用场景：在提交操作时检查暂存需求
 func (G *Gcm) HasUnstagedChanges() (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES").ExecTake("git", "diff", "--quiet")
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES"), "git", "diff", "--quiet")
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -62,7 +63,7 @@ func (G *Gcm) HasUnstagedChanges() (bool, error) {
 // 在检测到任何修改时返回 true，如果仓库干净则返回 false
 // 使用场景：在上下文切换时快速检查进行中的工作
 func (G *Gcm) HasChanges() (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES").ExecTake("git", "diff-index", "--quiet", "HEAD")
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "HAS-CHANGES"), "git", "diff-index", "--quiet", "HEAD")
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -84,7 +85,7 @@ func (G *Gcm) HasChanges() (bool, error) {
 // 如果仓库没有已暂存和未暂存更改则返回干净状态
 // 使用场景：在分支切换和发布等关键操作期间检查干净状态
 func (G *Gcm) GetStatusPorcelain() (string, error) {
-	output, err := G.execConfig.Exec("git", "status", "--porcelain")
+	output, err := G.exec("git", "status", "--porcelain")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -99,17 +100,17 @@ func (G *Gcm) GetStatusPorcelain() (string, error) {
 // 在没有找到暂存更改时返回带有问题状态的 Gcm 实例
 // 使用场景：如果工作路径干净则防止 commit 操作
 func (G *Gcm) CheckStagedChanges() *Gcm {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "HAS-STAGED-CHANGES").ExecTake("git", "diff-index", "--cached", "--quiet", "HEAD")
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "HAS-STAGED-CHANGES"), "git", "diff-index", "--cached", "--quiet", "HEAD")
 	if err != nil {
-		return newWaGcm(G.execConfig, []byte{}, err, G.debugMode)
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
 	}
 	switch exc {
 	case 1:
 		return G // Has staged changes // 有暂存的更改
 	case 0:
-		return newWaGcm(G.execConfig, []byte{}, errors.New("NON-STAGED-CHANGES"), G.debugMode)
+		return newWaGcm(G.execConfig, G.options, []byte{}, errors.New("NON-STAGED-CHANGES"), G.debugMode)
 	default:
-		return newWaGcm(G.execConfig, []byte{}, errors.Errorf("git diff-index failed with exit code %d", exc), G.debugMode)
+		return newWaGcm(G.execConfig, G.options, []byte{}, errors.Errorf("git diff-index failed with exit code %d", exc), G.debugMode)
 	}
 }
 
@@ -121,7 +122,7 @@ func (G *Gcm) CheckStagedChanges() *Gcm {
 // 返回标签名称、存在标志和可能的错误
 // 当没有标签时，返回 ("", false, nil)
 func (G *Gcm) GetLatestTag() (string, bool, error) {
-	output, exc, err := G.execConfig.NewConfig().WithExpectExit(128, "NO-TAGS").ExecTake("git", "describe", "--tags", "--abbrev=0")
+	output, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(128, "NO-TAGS"), "git", "describe", "--tags", "--abbrev=0")
 	if err != nil {
 		return "", false, erero.Wro(err)
 	}
@@ -136,54 +137,87 @@ func (G *Gcm) GetLatestTag() (string, bool, error) {
 }
 
 // GetLatestTagHasPrefix retrieves the latest tag name with the specified prefix
-// Returns the most recent tag that starts with the given prefix
+// Returns the highest tag that starts with the given prefix, by SemVer precedence like GetLatestVersion
+// Tags not parsing as versions rank below version tags and compare by name
 // Helps find version tags on specific subprojects and components
+// Note: the prefix matches as plain text, glob chars in it do not expand
 //
 // GetLatestTagHasPrefix 获取带有指定前缀的最新标签名称
-// 返回以给定前缀开头的最新标签
+// 返回以给定前缀开头、按 SemVer 优先级（与 GetLatestVersion 相同）最高的标签
+// 无法解析为版本的标签排在版本标签之下，并按名称比较
 // 帮助在特定子项目和组件上查找版本标签
+// 注意：前缀按纯文本匹配，其中的 glob 字符不会展开
 func (G *Gcm) GetLatestTagHasPrefix(prefix string) (string, error) {
 	if prefix == "" {
 		return "", erero.New("prefix is required")
 	}
-	// Validate prefix to prevent command injection // 验证prefix安全性，避免命令注入
-	if strings.Contains(prefix, "'") || strings.Contains(prefix, "`") || strings.Contains(prefix, "$") {
-		return "", erero.New("prefix contains unsafe characters")
-	}
-
-	// Use git tag -l to list tags matching prefix, version desc sort, take first // 使用 git tag -l 列出匹配前缀的标签，按版本号降序排序，取第一个
-	output, err := G.execConfig.NewConfig().WithBash().Exec(fmt.Sprintf("git tag -l --sort=-version:refname '%s*' | head -n 1", prefix))
+	tags, err := G.ListTags(TagQuery{})
 	if err != nil {
 		return "", erero.Wro(err)
 	}
-
-	return strings.TrimSpace(string(output)), nil
+	// Filter in process, the prefix stays plain text // 在进程内过滤，前缀保持为纯文本
+	var matched []string
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, prefix) {
+			matched = append(matched, tag)
+		}
+	}
+	return latestVersionTag(matched, func(string) string { return prefix }), nil
 }
 
 // GetLatestTagMatchGlob retrieves the latest tag matching glob pattern
-// Returns most recent tag that matches the given glob pattern, blank if none
+// Returns the highest tag that matches the given glob pattern by SemVer precedence, blank if none
+// Versions get parsed after the last "/", so component tags like "auth/v1.2.3" compare too
 // Supports wildcards in subproject and component versioning
 // Note: git tag -l uses glob (shell wildcard) patterns, not regular expressions
 //
 // GetLatestTagMatchGlob 获取匹配 shell glob 模式的最新标签
-// 返回匹配给定 glob 模式的最新标签和空字符串
+// 返回匹配给定 glob 模式、按 SemVer 优先级最高的标签，没有时为空字符串
+// 版本在最后一个 "/" 之后解析，因此 "auth/v1.2.3" 这样的组件标签也能比较
 // 在子项目和组件的版本管理期间支持通配符模式
 // 注意：git tag -l 使用 glob（shell 通配符）模式，而非正则表达式
 func (G *Gcm) GetLatestTagMatchGlob(globPattern string) (string, error) {
 	if globPattern == "" {
 		return "", erero.New("globPattern is required")
 	}
-	// Validate pattern to prevent command injection // 验证 globPattern 安全性，避免命令注入
-	if strings.Contains(globPattern, "'") || strings.Contains(globPattern, "`") || strings.Contains(globPattern, "$") {
-		return "", erero.New("globPattern contains unsafe characters")
-	}
-
-	// Use git tag -l with glob pattern, version desc sort, take first // 使用 git tag -l 匹配 glob 模式，按版本号降序排序，取第一个
-	output, err := G.execConfig.NewConfig().WithBash().Exec(fmt.Sprintf("git tag -l --sort=-version:refname '%s' | head -n 1", globPattern))
+	tags, err := G.ListTags(TagQuery{Patterns: []string{globPattern}})
 	if err != nil {
 		return "", erero.Wro(err)
 	}
-	return strings.TrimSpace(string(output)), nil
+	return latestVersionTag(tags, func(tag string) string { return tag[:strings.LastIndex(tag, "/")+1] }), nil
+}
+
+// latestVersionTag picks the highest tag by TagVersion.Compare, prefixOf gives the prefix to parse each tag with
+// Version tags rank above other tags, ties and other tags fall back to name order, blank when tags is empty
+//
+// latestVersionTag 按 TagVersion.Compare 选出最高的标签，prefixOf 给出解析每个标签所用的前缀
+// 版本标签排在其他标签之上，相等时以及其他标签按名称排序，tags 为空时返回空字符串
+func latestVersionTag(tags []string, prefixOf func(tag string) string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	versions := make(map[string]*TagVersion, len(tags))
+	for _, tag := range tags {
+		if version, err := ParseTagVersion(tag, prefixOf(tag)); err == nil {
+			versions[tag] = version
+		}
+	}
+	sorted := slices.Clone(tags)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		versionA, versionB := versions[a], versions[b]
+		switch {
+		case versionA != nil && versionB != nil:
+			if c := versionA.Compare(versionB); c != 0 {
+				return c
+			}
+		case versionA != nil:
+			return 1
+		case versionB != nil:
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	return sorted[len(sorted)-1]
 }
 
 // GetCommitHash retrieves the commit hash on a specified branch and tag reference
@@ -197,7 +231,7 @@ func (G *Gcm) GetCommitHash(refName string) (string, error) {
 	if refName == "" {
 		return "", erero.New("refName is required")
 	}
-	output, err := G.execConfig.Exec("git", "rev-parse", refName)
+	output, err := G.exec("git", "rev-parse", refName)
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -211,8 +245,11 @@ func (G *Gcm) GetCommitHash(refName string) (string, error) {
 // GetSortedTags 获取项目标签的排序列表及日期
 // 返回按创建日期升序排列的标签和日期格式化字符串
 // 使用场景：检查标签内容以选择下一个版本编号
+//
+// Deprecated: use ListTagInfos, it returns typed tags with type, target, tagger and message.
+// 已弃用：请使用 ListTagInfos，它返回带有类型、目标、标记者和消息的类型化标签。
 func (G *Gcm) GetSortedTags() (string, error) {
-	output, err := G.execConfig.Exec("git", "for-each-ref", "--sort=creatordate", "--format=%(refname) %(creatordate)", "refs/tags")
+	output, err := G.exec("git", "for-each-ref", "--sort=creatordate", "--format=%(refname) %(creatordate)", "refs/tags")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -227,7 +264,7 @@ func (G *Gcm) GetSortedTags() (string, error) {
 // 如果不在 Git 仓库则返回顶层路径和错误
 // 使用场景：导航到项目基础和解析路径
 func (G *Gcm) GetTopPath() (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--show-toplevel")
+	output, err := G.exec("git", "rev-parse", "--show-toplevel")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -243,7 +280,7 @@ func (G *Gcm) GetTopPath() (string, error) {
 // 使用场景：访问 Git 元数据、钩子和配置文件
 func (G *Gcm) GetGitDIRAbsPath() (string, error) {
 	// Use --absolute-git-dir instead of --git-dir to get absolute path // 使用 --absolute-git-dir 替代 --git-dir 以获得更好的可用性
-	output, err := G.execConfig.Exec("git", "rev-parse", "--absolute-git-dir")
+	output, err := G.exec("git", "rev-parse", "--absolute-git-dir")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -258,7 +295,7 @@ func (G *Gcm) GetGitDIRAbsPath() (string, error) {
 // 如果在子目录则返回如 "../" 的路径，如果在基础则返回空字符串
 // 使用场景：构建到基础级别资源的路径
 func (G *Gcm) GetSubPathToRoot() (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--show-cdup")
+	output, err := G.exec("git", "rev-parse", "--show-cdup")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -273,7 +310,7 @@ func (G *Gcm) GetSubPathToRoot() (string, error) {
 // 如果在子目录则返回如 "subpath/" 的路径，如果在基础则返回空字符串
 // 使用场景：查找在项目结构中的当前位置
 func (G *Gcm) GetSubPath() (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--show-prefix")
+	output, err := G.exec("git", "rev-parse", "--show-prefix")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -286,7 +323,7 @@ func (G *Gcm) GetSubPath() (string, error) {
 // IsInsideWorkTree 检查当前路径是否在 Git 工作树中
 // 如果在 Git 项目中则返回 true，否则返回 false
 func (G *Gcm) IsInsideWorkTree() (bool, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--is-inside-work-tree")
+	output, err := G.exec("git", "rev-parse", "--is-inside-work-tree")
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -299,7 +336,7 @@ func (G *Gcm) IsInsideWorkTree() (bool, error) {
 // GetCurrentBranch 获取当前分支的名称
 // 如果不在 Git 仓库中则返回当前分支名称和错误
 func (G *Gcm) GetCurrentBranch() (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := G.exec("git", "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -315,7 +352,7 @@ func (G *Gcm) GetRemoteURL(remoteName string) (string, error) {
 	if remoteName == "" {
 		remoteName = "origin"
 	}
-	output, err := G.execConfig.Exec("git", "remote", "get-url", remoteName)
+	output, err := G.exec("git", "remote", "get-url", remoteName)
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -328,7 +365,7 @@ func (G *Gcm) GetRemoteURL(remoteName string) (string, error) {
 // GetCommitCount 获取当前分支的提交总数
 // 如果不在 Git 仓库中或没有提交则返回提交数量和错误
 func (G *Gcm) GetCommitCount() (int, error) {
-	output, err := G.execConfig.Exec("git", "rev-list", "--count", "HEAD")
+	output, err := G.exec("git", "rev-list", "--count", "HEAD")
 	if err != nil {
 		return 0, erero.Wro(err)
 	}
@@ -345,7 +382,7 @@ func (G *Gcm) GetCommitCount() (int, error) {
 // ListBranches 获取所有本地分支名称列表
 // 如果不在 Git 仓库中则返回分支名称切片和错误
 func (G *Gcm) ListBranches() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "branch", "--format=%(refname:short)")
+	output, err := G.exec("git", "branch", "--format=%(refname:short)")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -366,7 +403,7 @@ func (G *Gcm) ListBranches() ([]string, error) {
 // ListRemoteBranches 获取所有远程分支名称列表
 // 如果不在 Git 仓库中则返回远程分支名称切片和错误
 func (G *Gcm) ListRemoteBranches() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "branch", "-r", "--format=%(refname:short)")
+	output, err := G.exec("git", "branch", "-r", "--format=%(refname:short)")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -393,7 +430,7 @@ func (G *Gcm) GetLogOneLine(limit int) ([]string, error) {
 	if limit >= 10000 {
 		return nil, erero.New("limit must < 10000")
 	}
-	output, err := G.execConfig.Exec("git", "log", "--oneline", fmt.Sprintf("-n%d", limit))
+	output, err := G.exec("git", "log", "--oneline", fmt.Sprintf("-n%d", limit))
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -415,7 +452,7 @@ func (G *Gcm) GetLogOneLine(limit int) ([]string, error) {
 // 从 Git 命令输出返回提交哈希字符串
 // 使用场景：在记录状态和创建引用时识别提交位置
 func (G *Gcm) GetCurrentCommitHash() (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "HEAD")
+	output, err := G.exec("git", "rev-parse", "HEAD")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -430,7 +467,7 @@ func (G *Gcm) GetCurrentCommitHash() (string, error) {
 // 返回包含主题和消息的提交消息文本
 // 使用场景：在审查更改和生成注释时检查提交内容
 func (G *Gcm) GetCommitMessage(ref string) (string, error) {
-	output, err := G.execConfig.Exec("git", "log", "-1", "--pretty=format:%B", ref)
+	output, err := G.exec("git", "log", "-1", "--pretty=format:%B", ref)
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -445,7 +482,7 @@ func (G *Gcm) GetCommitMessage(ref string) (string, error) {
 // 如果分支存在则返回 true，否则返回 false
 // 使用场景：在切换和创建分支时验证分支名称
 func (G *Gcm) BranchExists(name string) (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST").ExecTake("git", "show-ref", "--verify", "--quiet", "refs/heads/"+name)
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST"), "git", "show-ref", "--verify", "--quiet", "refs/heads/"+name)
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -467,7 +504,7 @@ func (G *Gcm) BranchExists(name string) (bool, error) {
 // 如果远程分支存在则返回 true，否则返回 false
 // 使用场景：在获取和跟踪时验证远程分支引用
 func (G *Gcm) RemoteBranchExists(name string) (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST").ExecTake("git", "show-ref", "--verify", "--quiet", "refs/remotes/"+name)
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST"), "git", "show-ref", "--verify", "--quiet", "refs/remotes/"+name)
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -489,7 +526,7 @@ func (G *Gcm) RemoteBranchExists(name string) (bool, error) {
 // 如果标签存在则返回 true，否则返回 false
 // 使用场景：防止重复创建标签和验证标签引用
 func (G *Gcm) TagExists(name string) (bool, error) {
-	_, exc, err := G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST").ExecTake("git", "show-ref", "--verify", "--quiet", "refs/tags/"+name)
+	_, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NOT-EXIST"), "git", "show-ref", "--verify", "--quiet", "refs/tags/"+name)
 	if err != nil {
 		return false, erero.Wro(err)
 	}
@@ -511,7 +548,7 @@ func (G *Gcm) TagExists(name string) (bool, error) {
 // 返回 Git 跟踪的文件路径
 // 使用场景：检查仓库内容并在处理资源时验证文件存在
 func (G *Gcm) GetTrackedFiles() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "ls-files")
+	output, err := G.exec("git", "ls-files")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -533,7 +570,7 @@ func (G *Gcm) GetTrackedFiles() ([]string, error) {
 // 返回工作路径中但不在版本管理中的文件路径
 // 使用场景：在暂存更改和清理工作空间时识别新文件
 func (G *Gcm) GetUntrackedFiles() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "ls-files", "--others", "--exclude-standard")
+	output, err := G.exec("git", "ls-files", "--others", "--exclude-standard")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -555,7 +592,7 @@ func (G *Gcm) GetUntrackedFiles() ([]string, error) {
 // 返回工作路径和暂存区中已更改文件的路径
 // 使用场景：在审查更改和选择性暂存时识别受影响的文件
 func (G *Gcm) GetModifiedFiles() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "diff", "--name-only", "HEAD")
+	output, err := G.exec("git", "diff", "--name-only", "HEAD")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -577,7 +614,7 @@ func (G *Gcm) GetModifiedFiles() ([]string, error) {
 // 返回格式为 "remote/branch" 的上游分支名称
 // 使用场景：验证跟踪配置和了解远程连接
 func (G *Gcm) GetUpstreamBranch(branch string) (string, error) {
-	output, err := G.execConfig.Exec("git", "rev-parse", "--abbrev-ref", branch+"@{upstream}")
+	output, err := G.exec("git", "rev-parse", "--abbrev-ref", branch+"@{upstream}")
 	if err != nil {
 		return "", erero.Wro(err)
 	}
@@ -592,7 +629,7 @@ func (G *Gcm) GetUpstreamBranch(branch string) (string, error) {
 // 返回匹配 gitignore 规则的文件路径
 // 使用场景：在清理工作空间和检查配置时识别被忽略的文件
 func (G *Gcm) GetIgnoredFiles() ([]string, error) {
-	output, err := G.execConfig.Exec("git", "status", "--ignored", "-s", "--", ".")
+	output, err := G.exec("git", "status", "--ignored", "-s", "--", ".")
 	if err != nil {
 		return nil, erero.Wro(err)
 	}
@@ -615,7 +652,7 @@ func (G *Gcm) GetIgnoredFiles() ([]string, error) {
 // 执行 'git config <key>' 获取指定设置
 // 使用场景：读取 user.name、user.email 和自定义配置值
 func (G *Gcm) ConfigGet(key string) (string, error) {
-	output, err := G.execConfig.Exec("git", "config", key)
+	output, err := G.exec("git", "config", key)
 	if err != nil {
 		return "", erero.Wro(err)
 	}
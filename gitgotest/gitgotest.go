@@ -0,0 +1,192 @@
+// Package gitgotest provides a scripted fake Runner to unit-test code driving gitgo
+// Expectations match argv in sequence and return canned stdout, stderr and exit codes
+// Runs without a git binary and without temp repos, fast and deterministic
+//
+// gitgotest 提供脚本化的假 Runner，用于对驱动 gitgo 的代码进行单元测试
+// 预期按顺序匹配 argv 并返回预设的 stdout、stderr 和退出码
+// 无需 git 程序和临时仓库，快速且结果确定
+package gitgotest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/pkg/errors"
+)
+
+// FakeRunner is a gitgo.Runner replaying scripted expectations in sequence
+// Safe to share between goroutines, mismatches fail the command and get reported in AssertDone
+//
+// FakeRunner 是按顺序重放脚本化预期的 gitgo.Runner
+// 可在协程间共享，不匹配时命令失败并在 AssertDone 中报告
+type FakeRunner struct {
+	mutex    sync.Mutex
+	expects  []*Expect  // Scripted expectations in sequence // 按顺序的脚本化预期
+	calls    [][]string // Argv of each received command // 每个收到命令的 argv
+	problems []string   // Mismatch descriptions // 不匹配描述
+}
+
+// NewFakeRunner creates a FakeRunner with no expectations
+//
+// NewFakeRunner 创建没有预期的 FakeRunner
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{}
+}
+
+// Expect appends an expectation matching the full argv, like Expect("git", "status")
+// Returns the Expect to script its stdout, stderr and exit code
+//
+// Expect 追加一个匹配完整 argv 的预期，如 Expect("git", "status")
+// 返回 Expect 以编写其 stdout、stderr 和退出码
+func (r *FakeRunner) Expect(argv ...string) *Expect {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	expect := &Expect{argv: slices.Clone(argv)}
+	r.expects = append(r.expects, expect)
+	return expect
+}
+
+// Run matches the command with the next expectation and returns its canned outcome
+// Streams the canned stdout into command.Stdout when the command asks for streaming
+//
+// Run 将命令与下一个预期匹配并返回其预设结果
+// 当命令要求流式输出时将预设 stdout 写入 command.Stdout
+func (r *FakeRunner) Run(ctx context.Context, command *gitgo.Command) (*gitgo.Result, error) {
+	expect, err := r.take(command.Argv())
+	if err != nil {
+		return &gitgo.Result{ExitCode: -1}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return &gitgo.Result{ExitCode: -1}, err
+	}
+	result := &gitgo.Result{
+		Output:   append(slices.Clone(expect.stdout), expect.stderr...),
+		Stdout:   slices.Clone(expect.stdout),
+		Stderr:   slices.Clone(expect.stderr),
+		ExitCode: expect.exitCode,
+	}
+	if command.Stdout != nil {
+		if _, err := command.Stdout.Write(expect.stdout); err != nil {
+			return &gitgo.Result{ExitCode: -1}, err
+		}
+		result.Output = slices.Clone(expect.stderr)
+		result.Stdout = nil
+	}
+	if expect.err != nil {
+		return result, expect.err
+	}
+	if expect.exitCode != 0 {
+		return result, errors.Errorf("exit status %d", expect.exitCode)
+	}
+	return result, nil
+}
+
+// take records the call and pops the next expectation when argv matches
+//
+// take 记录调用并在 argv 匹配时弹出下一个预期
+func (r *FakeRunner) take(argv []string) (*Expect, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.calls = append(r.calls, argv)
+	if len(r.expects) == 0 {
+		problem := fmt.Sprintf("unexpected command: %s", strings.Join(argv, " "))
+		r.problems = append(r.problems, problem)
+		return nil, errors.Errorf("gitgotest: %s", problem)
+	}
+	expect := r.expects[0]
+	if !slices.Equal(expect.argv, argv) {
+		problem := fmt.Sprintf("command mismatch: got %q, want %q", strings.Join(argv, " "), strings.Join(expect.argv, " "))
+		r.problems = append(r.problems, problem)
+		return nil, errors.Errorf("gitgotest: %s", problem)
+	}
+	r.expects = r.expects[1:]
+	return expect, nil
+}
+
+// Calls returns the argv of each received command in sequence
+//
+// Calls 按顺序返回每个收到命令的 argv
+func (r *FakeRunner) Calls() [][]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+// Remaining returns the count of expectations not yet consumed
+//
+// Remaining 返回尚未消费的预期数量
+func (r *FakeRunner) Remaining() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return len(r.expects)
+}
+
+// AssertDone fails the test when mismatches happened or expectations remain
+//
+// AssertDone 在发生不匹配或仍有剩余预期时使测试失败
+func (r *FakeRunner) AssertDone(t testing.TB) {
+	t.Helper()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, problem := range r.problems {
+		t.Errorf("gitgotest: %s", problem)
+	}
+	for _, expect := range r.expects {
+		t.Errorf("gitgotest: expected command not run: %s", strings.Join(expect.argv, " "))
+	}
+}
+
+// Expect is one scripted command with its canned outcome
+//
+// Expect 是一个脚本化命令及其预设结果
+type Expect struct {
+	argv     []string // Expected argv // 预期的 argv
+	stdout   []byte   // Canned stdout // 预设 stdout
+	stderr   []byte   // Canned stderr // 预设 stderr
+	exitCode int      // Canned exit code // 预设退出码
+	err      error    // Canned failure besides exit codes // 退出码之外的预设失败
+}
+
+// Stdout sets the canned stdout
+//
+// Stdout 设置预设 stdout
+func (e *Expect) Stdout(stdout string) *Expect {
+	e.stdout = []byte(stdout)
+	return e
+}
+
+// Stderr sets the canned stderr
+//
+// Stderr 设置预设 stderr
+func (e *Expect) Stderr(stderr string) *Expect {
+	e.stderr = []byte(stderr)
+	return e
+}
+
+// ExitCode sets the canned exit code, non-zero makes the command fail
+//
+// ExitCode 设置预设退出码，非零会使命令失败
+func (e *Expect) ExitCode(exitCode int) *Expect {
+	e.exitCode = exitCode
+	return e
+}
+
+// Fail makes the command fail with err, like a start failure, and exit code -1
+//
+// Fail 使命令以 err 失败（如启动失败），退出码为 -1
+func (e *Expect) Fail(err error) *Expect {
+	e.err = err
+	e.exitCode = -1
+	return e
+}
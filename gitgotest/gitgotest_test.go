@@ -0,0 +1,115 @@
+package gitgotest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/go-xlan/gitgo/gitgotest"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/erero"
+	"github.com/yyle88/rese"
+)
+
+// TestFakeRunner_GetCurrentBranch tests canned stdout flowing into query helpers
+//
+// TestFakeRunner_GetCurrentBranch 测试预设 stdout 流入查询辅助函数
+func TestFakeRunner_GetCurrentBranch(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "rev-parse", "--abbrev-ref", "HEAD").Stdout("feature\n")
+
+	gcm := gitgo.New("/fake/repo").WithRunner(runner)
+	require.Equal(t, "feature", rese.C1(gcm.GetCurrentBranch()))
+	require.Equal(t, [][]string{{"git", "rev-parse", "--abbrev-ref", "HEAD"}}, runner.Calls())
+	runner.AssertDone(t)
+}
+
+// TestFakeRunner_MergeConflict tests canned failures getting classified as GitError
+//
+// TestFakeRunner_MergeConflict 测试预设失败被分类为 GitError
+func TestFakeRunner_MergeConflict(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "merge", "feature").
+		Stdout("CONFLICT (content): Merge conflict in a.txt\nAutomatic merge failed; fix conflicts and then commit the result.\n").
+		ExitCode(1)
+
+	err := gitgo.New("/fake/repo").WithRunner(runner).Merge("feature").Reason()
+	require.Error(t, err)
+	require.True(t, gitgo.IsMergeConflict(err))
+	gitErr, ok := gitgo.AsGitError(err)
+	require.True(t, ok)
+	require.Equal(t, 1, gitErr.ExitCode)
+	runner.AssertDone(t)
+}
+
+// TestFakeRunner_HasChanges tests exit code 1 being taken as a valid answer
+//
+// TestFakeRunner_HasChanges 测试退出码 1 被视为有效结果
+func TestFakeRunner_HasChanges(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "diff-index", "--quiet", "HEAD").ExitCode(1)
+
+	gcm := gitgo.New("/fake/repo").WithRunner(runner)
+	require.True(t, rese.V1(gcm.HasChanges()))
+	runner.AssertDone(t)
+}
+
+// TestFakeRunner_LogSeq tests canned stdout streaming through iterators
+//
+// TestFakeRunner_LogSeq 测试预设 stdout 通过迭代器流式输出
+func TestFakeRunner_LogSeq(t *testing.T) {
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	record := hash + "\x00\x00Alice\x00alice@example.com\x002024-01-02T03:04:05+00:00\x00" +
+		"Alice\x00alice@example.com\x002024-01-02T03:04:05+00:00\x00initial\x00\x00\x00"
+
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "log", "-z", "--pretty=tformat:%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%s%x00%b%x00%(trailers:only,unfold)", "--end-of-options", "HEAD", "--").
+		Stdout(record)
+
+	var commits []gitgo.Commit
+	for commit, err := range gitgo.New("/fake/repo").WithRunner(runner).LogSeq(gitgo.LogOptions{}) {
+		require.NoError(t, err)
+		commits = append(commits, commit)
+	}
+	require.Len(t, commits, 1)
+	require.Equal(t, hash, commits[0].Hash)
+	require.Equal(t, "initial", commits[0].Subject)
+	runner.AssertDone(t)
+}
+
+// TestFakeRunner_Unexpected tests mismatches failing the command and staying visible
+//
+// TestFakeRunner_Unexpected 测试不匹配使命令失败并保持可见
+func TestFakeRunner_Unexpected(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "status")
+	runner.Expect("git", "push").Fail(erero.New("network down"))
+
+	gcm := gitgo.New("/fake/repo").WithRunner(runner)
+	require.Error(t, gcm.Fetch("origin").Reason())
+	require.Equal(t, 2, runner.Remaining())
+
+	require.NoError(t, gitgo.New("/fake/repo").WithRunner(runner).Status().Reason())
+	err := gitgo.New("/fake/repo").WithRunner(runner).Push().Reason()
+	require.ErrorContains(t, err, "network down")
+	require.Equal(t, 0, runner.Remaining())
+
+	recorder := &recordTB{TB: t}
+	runner.AssertDone(recorder)
+	require.Len(t, recorder.messages, 1)
+	require.Contains(t, recorder.messages[0], "git fetch origin")
+}
+
+// recordTB collects AssertDone failures without failing the outer test
+//
+// recordTB 收集 AssertDone 的失败信息而不使外层测试失败
+type recordTB struct {
+	testing.TB
+	messages []string
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Errorf(format string, args ...any) {
+	r.messages = append(r.messages, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,248 @@
+package gitgo
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// logFormat is the --pretty format of Log, fields split by NUL and commits ended by -z NUL
+// No field can contain NUL, so multi-line subjects, bodies and trailers never break parsing
+//
+// logFormat 是 Log 使用的 --pretty 格式，字段以 NUL 分隔，提交以 -z 的 NUL 结尾
+// 字段中不会出现 NUL，因此多行主题、正文和尾注不会破坏解析
+const logFormat = "%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%s%x00%b%x00%(trailers:only,unfold)"
+
+// logCommand is the command head of Log, the rev-list in cursor lookup shares the filters after it
+//
+// logCommand 是 Log 的命令头，游标查找中的 rev-list 共享其后的过滤参数
+var logCommand = []string{"log", "-z", "--pretty=tformat:" + logFormat}
+
+// logFieldCount is the count of NUL separated fields in each commit of logFormat
+//
+// logFieldCount 是 logFormat 中每个提交以 NUL 分隔的字段数量
+const logFieldCount = 11
+
+// Commit is one typed commit parsed from git log
+//
+// Commit 是从 git log 解析的一个类型化提交
+type Commit struct {
+	Hash           string    // Full commit hash // 完整提交哈希
+	Parents        []string  // Parent commit hashes, 2+ on merges // 父提交哈希，合并提交有 2 个以上
+	AuthorName     string    // Author name // 作者名称
+	AuthorEmail    string    // Author email // 作者邮箱
+	AuthorTime     time.Time // Author time // 作者时间
+	CommitterName  string    // Committer name // 提交者名称
+	CommitterEmail string    // Committer email // 提交者邮箱
+	CommitterTime  time.Time // Committer time // 提交时间
+	Subject        string    // First paragraph joined as one line // 首段合并为一行
+	Body           string    // Message text after the subject // 主题之后的消息文本
+	Trailers       []Trailer // Trailers like "Signed-off-by" // 尾注，如 "Signed-off-by"
+}
+
+// IsMerge checks if the commit has multiple parents
+//
+// IsMerge 检查提交是否有多个父提交
+func (c *Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// Trailer is one "Key: value" trailer line of a commit message
+//
+// Trailer 是提交消息中的一行 "Key: value" 尾注
+type Trailer struct {
+	Key   string // Trailer key // 尾注键
+	Value string // Trailer value // 尾注值
+}
+
+// LogOptions selects and pages commits of Log
+// Blank fields apply no filter, Revisions default to HEAD
+//
+// LogOptions 选择和分页 Log 的提交
+// 空字段不应用过滤，Revisions 默认为 HEAD
+type LogOptions struct {
+	Revisions   []string // Revisions and ranges like "v1.0.0..HEAD" // 修订和范围，如 "v1.0.0..HEAD"
+	Paths       []string // Limit to commits touching these paths // 限制为涉及这些路径的提交
+	Since       string   // --since date like "2024-01-01" and "2 weeks ago" // --since 日期，如 "2024-01-01" 和 "2 weeks ago"
+	Until       string   // --until date // --until 日期
+	Authors     []string // --author patterns, matching any of them // --author 模式，匹配任一即可
+	FirstParent bool     // Follow first parent only // 仅跟随第一父提交
+	MergesOnly  bool     // Merge commits only // 仅合并提交
+	NoMerges    bool     // Exclude merge commits // 排除合并提交
+	Limit       int      // Page size, 0 means no limit // 页大小，0 表示不限制
+	Skip        int      // Skip count before the page // 页前跳过的数量
+	After       string   // Cursor: start after this commit hash, the last hash of previous page // 游标：从该提交之后开始，即上一页最后的哈希
+}
+
+// Log gets typed commits selected with opts
+// Returns full hash, parents, author, committer, subject, body and trailers
+// Use case: build release notes and audit reports from commit history
+//
+// Log 获取由 opts 选择的类型化提交
+// 返回完整哈希、父提交、作者、提交者、主题、正文和尾注
+// 使用场景：从提交历史构建发布说明和审计报告
+func (G *Gcm) Log(opts LogOptions) ([]Commit, error) {
+	args, err := opts.makeArgs(logCommand...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if opts.After != "" {
+		skip, err := G.countLogBefore(opts)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		pageOpts := opts
+		pageOpts.After = ""
+		pageOpts.Skip += skip
+		if args, err = pageOpts.makeArgs(logCommand...); err != nil {
+			return nil, erero.Wro(err)
+		}
+	}
+	output, err := G.execStdout("git", args...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseLogOutput(output)
+}
+
+// countLogBefore counts commits up to and including the After cursor in the selection
+// The Skip of opts gets applied after the cursor, so it is left out here
+// Lists hashes alone with rev-list, so the cost stays small on long histories
+//
+// countLogBefore 统计选择结果中截至 After 游标（含）的提交数
+// opts 的 Skip 在游标之后生效，因此此处不计入
+// 仅用 rev-list 列出哈希，因此在长历史上开销仍然很小
+func (G *Gcm) countLogBefore(opts LogOptions) (int, error) {
+	cursorOpts := opts
+	cursorOpts.After = ""
+	cursorOpts.Limit = 0
+	cursorOpts.Skip = 0
+	args, err := cursorOpts.makeArgs("rev-list")
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	output, err := G.execStdout("git", args...)
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	for idx, hash := range strings.Fields(string(output)) {
+		if hash == opts.After {
+			return idx + 1, nil
+		}
+	}
+	return 0, erero.Errorf("cursor %s not found in log selection", opts.After)
+}
+
+// makeArgs builds git args from the options following the command head
+// Puts --end-of-options before revisions so values starting with "-" cannot inject flags
+//
+// makeArgs 在命令头之后根据选项构建 git 参数
+// 在修订前放置 --end-of-options，避免以 "-" 开头的值注入参数
+func (opts *LogOptions) makeArgs(head ...string) ([]string, error) {
+	if opts.MergesOnly && opts.NoMerges {
+		return nil, erero.New("MergesOnly and NoMerges are exclusive")
+	}
+	if opts.Limit < 0 || opts.Skip < 0 {
+		return nil, erero.New("Limit and Skip must >= 0")
+	}
+	args := append([]string{}, head...)
+	if opts.Since != "" {
+		args = append(args, "--since="+opts.Since)
+	}
+	if opts.Until != "" {
+		args = append(args, "--until="+opts.Until)
+	}
+	for _, author := range opts.Authors {
+		args = append(args, "--author="+author)
+	}
+	if opts.FirstParent {
+		args = append(args, "--first-parent")
+	}
+	if opts.MergesOnly {
+		args = append(args, "--merges")
+	}
+	if opts.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if opts.Limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(opts.Limit))
+	}
+	if opts.Skip > 0 {
+		args = append(args, "--skip="+strconv.Itoa(opts.Skip))
+	}
+	args = append(args, "--end-of-options")
+	if len(opts.Revisions) > 0 {
+		args = append(args, opts.Revisions...)
+	} else {
+		args = append(args, "HEAD")
+	}
+	args = append(args, "--")
+	args = append(args, opts.Paths...)
+	return args, nil
+}
+
+// parseLogOutput parses the NUL separated fields of logFormat into commits
+//
+// parseLogOutput 将 logFormat 以 NUL 分隔的字段解析为提交
+func parseLogOutput(output []byte) ([]Commit, error) {
+	fields := bytes.Split(output, []byte{0})
+	// Output ends with NUL, leaving one blank tail item // 输出以 NUL 结尾，留下一个空尾项
+	if len(fields) > 0 && len(fields[len(fields)-1]) == 0 {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields)%logFieldCount != 0 {
+		return nil, erero.Errorf("wrong log output field count %d", len(fields))
+	}
+	commits := make([]Commit, 0, len(fields)/logFieldCount)
+	for idx := 0; idx < len(fields); idx += logFieldCount {
+		commit, err := parseLogFields(fields[idx : idx+logFieldCount])
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// parseLogFields parses the fields of one commit in logFormat sequence
+//
+// parseLogFields 按 logFormat 顺序解析一个提交的字段
+func parseLogFields(fields [][]byte) (Commit, error) {
+	authorTime, err := time.Parse(time.RFC3339, string(fields[4]))
+	if err != nil {
+		return Commit{}, erero.Wro(err)
+	}
+	committerTime, err := time.Parse(time.RFC3339, string(fields[7]))
+	if err != nil {
+		return Commit{}, erero.Wro(err)
+	}
+	return Commit{
+		Hash:           string(fields[0]),
+		Parents:        strings.Fields(string(fields[1])),
+		AuthorName:     string(fields[2]),
+		AuthorEmail:    string(fields[3]),
+		AuthorTime:     authorTime,
+		CommitterName:  string(fields[5]),
+		CommitterEmail: string(fields[6]),
+		CommitterTime:  committerTime,
+		Subject:        string(fields[8]),
+		Body:           strings.TrimSpace(string(fields[9])),
+		Trailers:       parseTrailers(string(fields[10])),
+	}, nil
+}
+
+// parseTrailers parses unfolded "Key: value" lines into trailers
+//
+// parseTrailers 将展开后的 "Key: value" 行解析为尾注
+func parseTrailers(text string) []Trailer {
+	var trailers []Trailer
+	for _, line := range strings.Split(text, "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok && key != "" {
+			trailers = append(trailers, Trailer{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+		}
+	}
+	return trailers
+}
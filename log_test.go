@@ -0,0 +1,136 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/osexec"
+	"github.com/yyle88/rese"
+)
+
+// newLogTestRepo creates a repo with linear commits, a merge and distinct authors
+// History (newest first): merge, "main work", "feature work", "docs", "initial"
+//
+// newLogTestRepo 创建包含线性提交、合并和不同作者的仓库
+// 历史（从新到旧）：merge、"main work"、"feature work"、"docs"、"initial"
+func newLogTestRepo(t *testing.T) (*gitgo.Gcm, string) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-log-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	must.Done(os.MkdirAll(filepath.Join(tempDIR, "docs"), 0755))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "docs", "readme.md"), []byte("docs"), 0644))
+	gcm.Add().Commit("docs\n\nfirst body line\nsecond body line\n\nSigned-off-by: Alice <alice@example.com>\nRefs: #12").Done()
+
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b.txt"), []byte("b"), 0644))
+	gitgo.New(tempDIR).UpdateCommandConfig(func(cfg *osexec.CommandConfig) {
+		cfg.WithEnvs([]string{"GIT_AUTHOR_NAME=Bob", "GIT_AUTHOR_EMAIL=bob@example.com"})
+	}).Add().Commit("feature work").Done()
+
+	gcm.Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "c.txt"), []byte("c"), 0644))
+	gcm.Add().Commit("main work").Done()
+	gcm.UpdateCommandConfig(func(cfg *osexec.CommandConfig) {
+		cfg.WithEnvs([]string{"GIT_MERGE_AUTOEDIT=no"})
+	}).Merge("feature").Done()
+	return gcm, tempDIR
+}
+
+// TestGcm_Log tests typed commits with parents, authors, body and trailers
+// Verifies merge detection and multi-line message parsing
+//
+// TestGcm_Log 测试包含父提交、作者、正文和尾注的类型化提交
+// 验证合并检测和多行消息解析
+func TestGcm_Log(t *testing.T) {
+	gcm, _ := newLogTestRepo(t)
+
+	commits := rese.V1(gcm.Log(gitgo.LogOptions{}))
+	require.Len(t, commits, 5)
+	require.True(t, commits[0].IsMerge())
+	require.Len(t, commits[0].Parents, 2)
+	require.Equal(t, "initial", commits[4].Subject)
+	require.Empty(t, commits[4].Parents)
+
+	var docs gitgo.Commit
+	for _, commit := range commits {
+		require.Len(t, commit.Hash, 40)
+		require.False(t, commit.AuthorTime.IsZero())
+		require.False(t, commit.CommitterTime.IsZero())
+		if commit.Subject == "docs" {
+			docs = commit
+		}
+	}
+	require.Contains(t, docs.Body, "first body line\nsecond body line")
+	require.Equal(t, []gitgo.Trailer{
+		{Key: "Signed-off-by", Value: "Alice <alice@example.com>"},
+		{Key: "Refs", Value: "#12"},
+	}, docs.Trailers)
+}
+
+// TestGcm_Log_Filters tests revision range, path, author and merge filters
+// Verifies each option narrows the selection as git log does
+//
+// TestGcm_Log_Filters 测试修订范围、路径、作者和合并过滤
+// 验证每个选项像 git log 一样缩小选择范围
+func TestGcm_Log_Filters(t *testing.T) {
+	gcm, _ := newLogTestRepo(t)
+
+	subjects := func(opts gitgo.LogOptions) []string {
+		var results []string
+		for _, commit := range rese.V1(gcm.Log(opts)) {
+			results = append(results, commit.Subject)
+		}
+		return results
+	}
+
+	require.Equal(t, []string{"docs"}, subjects(gitgo.LogOptions{Paths: []string{"docs"}}))
+	require.Equal(t, []string{"feature work"}, subjects(gitgo.LogOptions{Authors: []string{"Bob"}}))
+	require.Equal(t, []string{"feature work"}, subjects(gitgo.LogOptions{Revisions: []string{"main~1..feature"}}))
+	require.Len(t, subjects(gitgo.LogOptions{MergesOnly: true}), 1)
+	require.Len(t, subjects(gitgo.LogOptions{NoMerges: true}), 4)
+	require.Len(t, subjects(gitgo.LogOptions{FirstParent: true}), 4)
+	require.Empty(t, subjects(gitgo.LogOptions{Since: "2099-01-01"}))
+	require.Len(t, subjects(gitgo.LogOptions{Until: "2099-01-01"}), 5)
+
+	_, err := gcm.Log(gitgo.LogOptions{MergesOnly: true, NoMerges: true})
+	require.Error(t, err)
+}
+
+// TestGcm_Log_Pagination tests cursor-style paging with Limit and After
+// Verifies pages chain together to cover the full history without overlap
+//
+// TestGcm_Log_Pagination 测试基于 Limit 和 After 的游标式分页
+// 验证各页首尾相连、无重叠地覆盖完整历史
+func TestGcm_Log_Pagination(t *testing.T) {
+	gcm, _ := newLogTestRepo(t)
+
+	all := rese.V1(gcm.Log(gitgo.LogOptions{}))
+
+	var paged []gitgo.Commit
+	opts := gitgo.LogOptions{Limit: 2}
+	for {
+		page := rese.V1(gcm.Log(opts))
+		if len(page) == 0 {
+			break
+		}
+		paged = append(paged, page...)
+		opts.After = page[len(page)-1].Hash
+	}
+	require.Equal(t, all, paged)
+
+	page := rese.V1(gcm.Log(gitgo.LogOptions{Limit: 2, Skip: 1, After: all[0].Hash}))
+	require.Equal(t, all[2:4], page)
+
+	_, err := gcm.Log(gitgo.LogOptions{After: "0000000000000000000000000000000000000000"})
+	require.Error(t, err)
+}
@@ -0,0 +1,249 @@
+package gitgo
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Middleware wraps the Runner executing commands, so callers can observe, veto or rewrite commands
+// Runs around each git invocation of Gcm chains, query helpers and streams
+//
+// Middleware 包装执行命令的 Runner，调用方可以观察、否决或改写命令
+// 在 Gcm 链、查询辅助函数和流的每次 git 调用周围运行
+type Middleware func(next Runner) Runner
+
+// ErrCommandDenied is the cause of commands vetoed by DenyMiddleware
+//
+// ErrCommandDenied 是被 DenyMiddleware 否决的命令的错误原因
+var ErrCommandDenied = errors.New("command denied")
+
+// DangerousCommands lists git subcommands that destroy work or rewrite shared history
+// Each entry is a subcommand followed by the flags that make it dangerous
+//
+// DangerousCommands 列出会破坏工作成果或改写共享历史的 git 子命令
+// 每个条目是一个子命令，后跟使其危险的标志
+var DangerousCommands = []string{
+	"push --force",
+	"push -f",
+	"push --force-with-lease",
+	"push --delete",
+	"push --mirror",
+	"reset --hard",
+	"clean",
+	"filter-branch",
+	"branch -D",
+	"update-ref -d",
+	"reflog expire",
+}
+
+// denyFlagAliases maps flags of a subcommand to the canonical flags deny rules compare against
+// So "-f", "--force-with-lease" and "--force-if-includes" of push all count as "--force"
+//
+// denyFlagAliases 将子命令的标志映射为否决规则比较时使用的规范标志
+// 因此 push 的 "-f"、"--force-with-lease" 和 "--force-if-includes" 都视为 "--force"
+var denyFlagAliases = map[string]map[string][]string{
+	"push": {
+		"-f":                  {"--force"},
+		"--force-with-lease":  {"--force"},
+		"--force-if-includes": {"--force"},
+		"-d":                  {"--delete"},
+	},
+	"branch": {
+		"-D": {"--delete", "--force"},
+		"-d": {"--delete"},
+		"-f": {"--force"},
+		"-M": {"--move", "--force"},
+		"-m": {"--move"},
+		"-C": {"--copy", "--force"},
+		"-c": {"--copy"},
+	},
+	"clean": {
+		"-f": {"--force"},
+		"-d": {"--dirs"},
+		"-x": {"--ignored"},
+	},
+	"update-ref": {
+		"--delete": {"-d"},
+	},
+}
+
+// IsCommandDenied checks if the command got vetoed by DenyMiddleware
+//
+// IsCommandDenied 检查命令是否被 DenyMiddleware 否决
+func IsCommandDenied(err error) bool {
+	return errors.Is(err, ErrCommandDenied)
+}
+
+// TimingMiddleware reports the duration and outcome of each command to observe
+// Use case: feed command latency metrics and slow command alerts
+//
+// TimingMiddleware 将每个命令的耗时和结果报告给 observe
+// 使用场景：提供命令延迟指标和慢命令告警
+func TimingMiddleware(observe func(command *Command, result *Result, duration time.Duration, err error)) Middleware {
+	return func(next Runner) Runner {
+		return RunnerFunc(func(ctx context.Context, command *Command) (*Result, error) {
+			startTime := time.Now()
+			result, err := next.Run(ctx, command)
+			observe(command, result, time.Since(startTime), err)
+			return result, err
+		})
+	}
+}
+
+// LoggingMiddleware logs each command with argv, path, exit code and duration using slog
+// Success logs at info level, failures (including expected exit codes) log at warn level
+// A nil logger means slog.Default()
+//
+// LoggingMiddleware 使用 slog 记录每个命令的 argv、路径、退出码和耗时
+// 成功以 info 级别记录，失败（包括预期的退出码）以 warn 级别记录
+// logger 为 nil 时使用 slog.Default()
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Runner) Runner {
+		return RunnerFunc(func(ctx context.Context, command *Command) (*Result, error) {
+			startTime := time.Now()
+			result, err := next.Run(ctx, command)
+			attrs := []slog.Attr{
+				slog.Any("argv", command.Argv()),
+				slog.String("path", command.Path),
+				slog.Duration("duration", time.Since(startTime)),
+			}
+			if result != nil {
+				attrs = append(attrs, slog.Int("exitCode", result.ExitCode))
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelWarn, "git command failed", append(attrs, slog.String("error", err.Error()))...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelInfo, "git command", attrs...)
+			}
+			return result, err
+		})
+	}
+}
+
+// DenyMiddleware vetoes commands matching the rules, failing them with ErrCommandDenied
+// A rule is a subcommand followed by flags, like "push --force", matching when each flag is present
+// Flags get normalized first, so "-f", "+main" refspecs and combined "-fu" match "push --force" too
+// Shell lines get checked per git invocation, pass DangerousCommands to block the usual suspects
+//
+// DenyMiddleware 否决匹配规则的命令，使其以 ErrCommandDenied 失败
+// 规则是子命令后跟标志，如 "push --force"，当每个标志都存在时匹配
+// 标志会先规范化，因此 "-f"、"+main" refspec 和组合的 "-fu" 也会匹配 "push --force"
+// shell 行按每次 git 调用检查，传入 DangerousCommands 可拦截常见的危险命令
+func DenyMiddleware(rules ...string) Middleware {
+	return func(next Runner) Runner {
+		return RunnerFunc(func(ctx context.Context, command *Command) (*Result, error) {
+			if rule, denied := matchDenyRules(command, rules); denied {
+				return &Result{ExitCode: -1}, errors.Wrapf(ErrCommandDenied, "%q matches deny rule %q", command.String(), rule)
+			}
+			return next.Run(ctx, command)
+		})
+	}
+}
+
+// matchDenyRules returns the first rule matching a git invocation of the command
+//
+// matchDenyRules 返回与命令中某次 git 调用匹配的第一条规则
+func matchDenyRules(command *Command, rules []string) (string, bool) {
+	argvs := [][]string{command.Argv()}
+	if command.ShellType != "" {
+		argvs = nil
+		line := strings.Join(command.Argv(), " ")
+		for _, segment := range strings.FieldsFunc(line, func(c rune) bool { return strings.ContainsRune("|;&\n", c) }) {
+			argvs = append(argvs, strings.Fields(segment))
+		}
+	}
+	for _, argv := range argvs {
+		subcommand, args, ok := splitGitArgs(argv)
+		if !ok {
+			continue
+		}
+		for _, rule := range rules {
+			words := strings.Fields(rule)
+			if len(words) == 0 || words[0] != subcommand {
+				continue
+			}
+			if hasAllFlags(normalizeDenyFlags(subcommand, args), normalizeDenyFlags(subcommand, words[1:])) {
+				return rule, true
+			}
+		}
+	}
+	return "", false
+}
+
+// hasAllFlags tells whether each normalized flag is present in the normalized args
+// Git takes unambiguous abbreviations of long flags, so "--har" counts as "--hard"
+//
+// hasAllFlags 判断每个规范化的标志是否都出现在规范化的 args 中
+// git 接受长标志的无歧义缩写，因此 "--har" 视为 "--hard"
+func hasAllFlags(args []string, flags []string) bool {
+	for _, flag := range flags {
+		if !slices.ContainsFunc(args, func(arg string) bool { return arg == flag || isLongFlagAbbrev(arg, flag) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLongFlagAbbrev tells whether arg is an abbreviation of the long flag, like "--forc" of "--force"
+//
+// isLongFlagAbbrev 判断 arg 是否为长标志的缩写，如 "--forc" 是 "--force" 的缩写
+func isLongFlagAbbrev(arg string, flag string) bool {
+	return len(arg) > 2 && strings.HasPrefix(arg, "--") && strings.HasPrefix(flag, arg)
+}
+
+// normalizeDenyFlags rewrites args into canonical flags, so other spellings cannot bypass deny rules
+// Drops "=value" parts, splits combined short flags like "-fu" and maps aliases with denyFlagAliases
+// Abbreviated long flags map like each alias they abbreviate, hasAllFlags matches the other abbreviations
+// Push refspecs starting with "+" count as "--force" and ones starting with ":" as "--delete"
+// Positional args stay as they are, args after "--" are paths and stay out
+//
+// normalizeDenyFlags 将 args 改写为规范标志，使其他写法无法绕过否决规则
+// 去掉 "=value" 部分，拆分 "-fu" 这类组合短标志，并通过 denyFlagAliases 映射别名
+// 缩写的长标志按其缩写的每个别名映射，其他缩写由 hasAllFlags 匹配
+// 以 "+" 开头的 push refspec 视为 "--force"，以 ":" 开头的视为 "--delete"
+// 位置参数保持原样，"--" 之后的参数是路径，不参与匹配
+func normalizeDenyFlags(subcommand string, args []string) []string {
+	var flags []string
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		var names []string
+		switch {
+		case subcommand == "push" && len(arg) > 1 && arg[0] == '+':
+			names = []string{"--force"}
+		case subcommand == "push" && len(arg) > 1 && arg[0] == ':':
+			names = []string{"--delete"}
+		case strings.HasPrefix(arg, "--"):
+			name, _, _ := strings.Cut(arg, "=")
+			names = []string{name}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			for _, c := range arg[1:] {
+				names = append(names, "-"+string(c))
+			}
+		default:
+			names = []string{arg} // Positional words like "expire" of "reflog expire" // 位置参数，如 "reflog expire" 中的 "expire"
+		}
+		for _, name := range names {
+			if aliases, ok := denyFlagAliases[subcommand][name]; ok {
+				flags = append(flags, aliases...)
+				continue
+			}
+			flags = append(flags, name)
+			for alias, aliases := range denyFlagAliases[subcommand] {
+				if isLongFlagAbbrev(name, alias) {
+					flags = append(flags, aliases...) // Like "--force-with-le" of push // 如 push 的 "--force-with-le"
+				}
+			}
+		}
+	}
+	return flags
+}
@@ -0,0 +1,170 @@
+package gitgo_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/go-xlan/gitgo/gitgotest"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Use tests middlewares wrapping commands in sequence and rewriting argv
+// Verifies the first middleware is the outermost and rewritten commands reach the Runner
+//
+// TestGcm_Use 测试中间件按顺序包装命令并改写 argv
+// 验证第一个中间件位于最外层且改写后的命令到达 Runner
+func TestGcm_Use(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "push", "--dry-run")
+
+	var trace []string
+	traceMiddleware := func(name string) gitgo.Middleware {
+		return func(next gitgo.Runner) gitgo.Runner {
+			return gitgo.RunnerFunc(func(ctx context.Context, command *gitgo.Command) (*gitgo.Result, error) {
+				trace = append(trace, name+" enter")
+				defer func() { trace = append(trace, name+" leave") }()
+				return next.Run(ctx, command)
+			})
+		}
+	}
+	rewrite := func(next gitgo.Runner) gitgo.Runner {
+		return gitgo.RunnerFunc(func(ctx context.Context, command *gitgo.Command) (*gitgo.Result, error) {
+			command.Args = append(command.Args, "--dry-run")
+			return next.Run(ctx, command)
+		})
+	}
+
+	gcm := gitgo.New("/fake/repo").WithRunner(runner).Use(traceMiddleware("outer"), traceMiddleware("inner"))
+	gcm.Use(rewrite).Push().Done()
+	require.Equal(t, []string{"outer enter", "inner enter", "inner leave", "outer leave"}, trace)
+	runner.AssertDone(t)
+}
+
+// TestDenyMiddleware tests vetoing dangerous commands before they reach the Runner
+// Verifies the denial short-circuits the chain and classifies with IsCommandDenied
+//
+// TestDenyMiddleware 测试在危险命令到达 Runner 前将其否决
+// 验证否决会短路链并可通过 IsCommandDenied 分类
+func TestDenyMiddleware(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "reset")
+	runner.Expect("git", "push", "origin", "main")
+	runner.Expect("git", "status")
+
+	gcm := gitgo.New("/fake/repo").WithRunner(runner).Use(gitgo.DenyMiddleware(gitgo.DangerousCommands...))
+	gcm.Reset().PushTo("origin", "main").Done()
+
+	err := gcm.ResetHard().Push().Reason()
+	require.True(t, gitgo.IsCommandDenied(err))
+	require.False(t, gitgo.IsCommandDenied(gcm.Status().Reason()))
+	runner.AssertDone(t)
+
+	// Policies still apply in dry-run mode, so previews reveal vetoes // dry-run 模式下策略仍然生效，预览可以发现否决
+	err = gitgo.New("/fake/repo").WithDryRun().Use(gitgo.DenyMiddleware(gitgo.DangerousCommands...)).ResetHard().Reason()
+	require.True(t, gitgo.IsCommandDenied(err))
+}
+
+// TestDenyMiddleware_Spellings tests deny rules against other spellings of the same flags
+// Verifies force refspecs, combined short flags, long forms and force aliases get denied
+//
+// TestDenyMiddleware_Spellings 测试否决规则对同一标志其他写法的匹配
+// 验证强制 refspec、组合短标志、长格式和强制别名都会被否决
+func TestDenyMiddleware_Spellings(t *testing.T) {
+	err := gitgo.New("/fake/repo").WithRunner(gitgotest.NewFakeRunner()).Use(gitgo.DenyMiddleware(gitgo.DangerousCommands...)).PushTo("origin", "+main").Reason()
+	require.True(t, gitgo.IsCommandDenied(err))
+
+	for _, args := range [][]string{
+		{"push", "origin", "+main"},
+		{"push", "origin", "+refs/heads/main:refs/heads/main"},
+		{"push", "-fu", "origin", "main"},
+		{"push", "--force-if-includes", "origin", "main"},
+		{"push", "--force-with-lease=main:abc123", "origin", "main"},
+		{"push", "origin", ":old-branch"},
+		{"push", "-d", "origin", "old-branch"},
+		{"branch", "-Df", "topic"},
+		{"branch", "-d", "-f", "topic"},
+		{"branch", "--delete", "--force", "topic"},
+		{"update-ref", "--delete", "refs/heads/topic"},
+		{"-C", "/fake/repo", "clean", "-fdx"},
+		{"reset", "--har"},
+		{"push", "--mirro", "origin"},
+		{"push", "--forc", "origin", "main"},
+		{"push", "--force-with-le", "origin", "main"},
+		{"branch", "--delete", "--forc", "topic"},
+		{"branch", "--del", "--force", "topic"},
+		{"update-ref", "--del", "refs/heads/topic"},
+		{"--git-dir", ".git", "reset", "--hard"},
+		{"--work-tree", ".", "reset", "--hard"},
+		{"--git-dir", ".git", "--work-tree", ".", "push", "--force"},
+		{"--namespace", "team", "push", "--delete", "origin", "topic"},
+	} {
+		runner := gitgotest.NewFakeRunner()
+		_, err := gitgo.DenyMiddleware(gitgo.DangerousCommands...)(runner).Run(context.Background(), &gitgo.Command{Name: "git", Args: args})
+		require.True(t, gitgo.IsCommandDenied(err), strings.Join(args, " "))
+		runner.AssertDone(t)
+	}
+
+	for _, args := range [][]string{
+		{"push", "-u", "origin", "main"},
+		{"branch", "-d", "topic"},
+		{"branch", "--", "-D"},
+		{"reflog", "show"},
+		{"reset", "--soft"},
+		{"push", "--follow-tags", "origin", "main"},
+		{"--git-dir", ".git", "reset", "HEAD~1"},
+		{"--work-tree", ".", "push", "origin", "main"},
+	} {
+		runner := gitgotest.NewFakeRunner()
+		runner.Expect(append([]string{"git"}, args...)...)
+		_, err := gitgo.DenyMiddleware(gitgo.DangerousCommands...)(runner).Run(context.Background(), &gitgo.Command{Name: "git", Args: args})
+		require.NoError(t, err, strings.Join(args, " "))
+		runner.AssertDone(t)
+	}
+}
+
+// TestTimingMiddleware tests observing duration and exit code of each command
+//
+// TestTimingMiddleware 测试观察每个命令的耗时和退出码
+func TestTimingMiddleware(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "rev-parse", "HEAD").Stdout("0123\n")
+	runner.Expect("git", "diff-index", "--quiet", "HEAD").ExitCode(1)
+
+	var exitCodes []int
+	gcm := gitgo.New("/fake/repo").WithRunner(runner).Use(gitgo.TimingMiddleware(func(command *gitgo.Command, result *gitgo.Result, duration time.Duration, err error) {
+		require.GreaterOrEqual(t, duration, time.Duration(0))
+		exitCodes = append(exitCodes, result.ExitCode)
+	}))
+	require.Equal(t, "0123", rese.C1(gcm.GetCurrentCommitHash()))
+	require.True(t, rese.V1(gcm.HasChanges()))
+	require.Equal(t, []int{0, 1}, exitCodes)
+	runner.AssertDone(t)
+}
+
+// TestLoggingMiddleware tests structured slog records of successful and failed commands
+//
+// TestLoggingMiddleware 测试成功和失败命令的结构化 slog 记录
+func TestLoggingMiddleware(t *testing.T) {
+	runner := gitgotest.NewFakeRunner()
+	runner.Expect("git", "status")
+	runner.Expect("git", "push").Stderr("rejected\n").ExitCode(1)
+
+	var buffer bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buffer, nil))
+	err := gitgo.New("/fake/repo").WithRunner(runner).Use(gitgo.LoggingMiddleware(logger)).Status().Push().Reason()
+	require.Error(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	require.Contains(t, string(lines[0]), `"level":"INFO"`)
+	require.Contains(t, string(lines[0]), `"argv":["git","status"]`)
+	require.Contains(t, string(lines[1]), `"level":"WARN"`)
+	require.Contains(t, string(lines[1]), `"exitCode":1`)
+	runner.AssertDone(t)
+}
@@ -10,6 +10,10 @@
 package gitgo
 
 import (
+	"context"
+	"slices"
+	"time"
+
 	"github.com/yyle88/eroticgo"
 	"github.com/yyle88/must/mustslice"
 	"github.com/yyle88/osexec"
@@ -25,6 +29,7 @@ import (
 // - output: Command output bytes from recent operations (both success and failures)
 // - errorOnce: First issue encountered in chain (becomes clear when operations succeed)
 // - debugMode: Activates detailed debug logging with colored console output
+// - options: Chain settings like context and timeout passed along to each next Gcm
 //
 // Gcm 代表 Git 命令引擎，支持链式调用和集成处理
 // 在方法调用间维护执行状态、输出捕获和调试信息
@@ -35,11 +40,27 @@ import (
 // - output: 来自最近操作的命令输出字节（成功和失败）
 // - errorOnce: 链中遇到的第一个错误（操作成功时为 nil）
 // - debugMode: 启用带有彩色控制台输出的详细调试日志
+// - options: 传递给链中下一个 Gcm 的设置，如上下文和超时
 type Gcm struct {
 	execConfig *osexec.ExecConfig // Execution configuration with path context // 执行配置和路径上下文
 	output     []byte             // Last command output bytes // 最后命令的输出字节
 	errorOnce  error              // First error in the chain // 链中的第一个错误
 	debugMode  bool               // Debug logging flag // 调试日志标志
+	options    gcmOptions         // Chain settings passed to next Gcm // 传递给下一个 Gcm 的链设置
+}
+
+// gcmOptions holds chain settings copied into each Gcm created along the chain
+// Kept as one value so adding settings does not touch each constructor call
+//
+// gcmOptions 保存复制到链中每个新建 Gcm 的链设置
+// 作为一个整体值保存，以便新增设置时不必修改每处构造调用
+type gcmOptions struct {
+	ctx         context.Context // Chain context, nil means background // 链上下文，nil 表示 background
+	timeout     time.Duration   // Per-command timeout, 0 means none // 单命令超时，0 表示无超时
+	runner      Runner          // Command runner, nil means os/exec // 命令执行器，nil 表示 os/exec
+	dryRun      bool            // Skip mutating commands when set // 设置时跳过修改性命令
+	recorder    *Recorder       // Command transcript sink, nil means none // 命令记录接收者，nil 表示不记录
+	middlewares []Middleware    // Middlewares around the runner, first is outermost // 包装执行器的中间件，第一个在最外层
 }
 
 // New creates a new Gcm instance with default configuration at the specified path
@@ -50,7 +71,7 @@ type Gcm struct {
 // 使用标准设置和执行上下文初始化 Git 命令引擎
 // 返回已配置和准备好的 Gcm 实例以进行链式 Git 操作
 func New(path string) *Gcm {
-	return newOkGcm(osexec.NewCommandConfig().WithPath(path).WithDebugMode(osexec.NewDebugMode(debugModeOpen)), make([]byte, 0), debugModeOpen)
+	return newOkGcm(osexec.NewCommandConfig().WithPath(path).WithDebugMode(osexec.NewDebugMode(debugModeOpen)), gcmOptions{}, make([]byte, 0), debugModeOpen)
 }
 
 // NewGcm creates a new Gcm instance with custom execution configuration
@@ -61,7 +82,7 @@ func New(path string) *Gcm {
 // 允许高级配置命令执行环境和行为
 // 在专门的 Git 操作需求出现时提供适配
 func NewGcm(path string, execConfig *osexec.ExecConfig) *Gcm {
-	return newOkGcm(execConfig.NewConfig().WithPath(path).WithDebugMode(osexec.NewDebugMode(debugModeOpen)), make([]byte, 0), debugModeOpen)
+	return newOkGcm(execConfig.NewConfig().WithPath(path).WithDebugMode(osexec.NewDebugMode(debugModeOpen)), gcmOptions{}, make([]byte, 0), debugModeOpen)
 }
 
 // newOkGcm creates success-state Gcm instance with green success logging in debug mode
@@ -71,7 +92,7 @@ func NewGcm(path string, execConfig *osexec.ExecConfig) *Gcm {
 // newOkGcm 在调试模式下创建带有绿色成功日志的成功状态 Gcm 实例
 // 该函数构建无错误的 Gcm 以继续命令链
 // 调试时显示带有命令输出详情的绿色成功消息
-func newOkGcm(execConfig *osexec.ExecConfig, output []byte, debugMode bool) *Gcm {
+func newOkGcm(execConfig *osexec.ExecConfig, options gcmOptions, output []byte, debugMode bool) *Gcm {
 	if debugMode {
 		if len(output) > 0 {
 			zaplog.ZAPS.Skip3.SUG.Debugln("done", "message:", "\n"+eroticgo.GREEN.Sprint(string(output))+"\n", "-")
@@ -84,6 +105,7 @@ func newOkGcm(execConfig *osexec.ExecConfig, output []byte, debugMode bool) *Gcm
 		output:     output,
 		errorOnce:  nil,
 		debugMode:  debugMode,
+		options:    options,
 	}
 }
 
@@ -94,7 +116,7 @@ func newOkGcm(execConfig *osexec.ExecConfig, output []byte, debugMode bool) *Gcm
 // newWaGcm 在调试模式下创建带有红色日志的失败状态 Gcm 实例
 // 该函数构建具有捕获错误的 Gcm 以停止命令链
 // 调试时显示带有错误详情的红色消息
-func newWaGcm(execConfig *osexec.ExecConfig, output []byte, errorOnce error, debugMode bool) *Gcm {
+func newWaGcm(execConfig *osexec.ExecConfig, options gcmOptions, output []byte, errorOnce error, debugMode bool) *Gcm {
 	if debugMode {
 		if len(output) > 0 {
 			zaplog.ZAPS.Skip3.SUG.Errorln("wrong", eroticgo.RED.Sprint(errorOnce), "message:", "\n"+eroticgo.RED.Sprint(string(output))+"\n", "-")
@@ -107,6 +129,7 @@ func newWaGcm(execConfig *osexec.ExecConfig, output []byte, errorOnce error, deb
 		output:     output,
 		errorOnce:  errorOnce,
 		debugMode:  debugMode,
+		options:    options,
 	}
 }
 
@@ -164,11 +187,29 @@ func (G *Gcm) do(name string, args ...string) *Gcm {
 	if G.errorOnce != nil {
 		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
 	}
-	output, err := G.execConfig.Exec(name, args...)
+	output, err := G.exec(name, args...)
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, output, err, G.debugMode)
+	}
+	return newOkGcm(G.execConfig, G.options, output, G.debugMode)
+}
+
+// doWith executes Git commands like do, using cfg in place of the shared execution configuration
+// Calls newWaGcm and newOkGcm itself, keeping the same Skip3 stack trace depth as do
+// Use case: commands needing extra envs like GIT_EDITOR without touching the chain config
+//
+// doWith 像 do 一样执行 Git 命令，使用 cfg 代替共享的执行配置
+// 自行调用 newWaGcm 和 newOkGcm，保持与 do 相同的 Skip3 堆栈跟踪深度
+// 使用场景：需要 GIT_EDITOR 等额外环境变量但不改动链配置的命令
+func (G *Gcm) doWith(cfg *osexec.ExecConfig, name string, args ...string) *Gcm {
+	if G.errorOnce != nil {
+		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
+	}
+	output, _, err := G.execTake(cfg, name, args...)
 	if err != nil {
-		return newWaGcm(G.execConfig, output, err, G.debugMode)
+		return newWaGcm(G.execConfig, G.options, output, err, G.debugMode)
 	}
-	return newOkGcm(G.execConfig, output, G.debugMode)
+	return newOkGcm(G.execConfig, G.options, output, G.debugMode)
 }
 
 // UpdateCommandConfig modifies the execution configuration using provided functions
@@ -219,6 +260,93 @@ func (G *Gcm) WithDebugMode(debugMode bool) *Gcm {
 	return G
 }
 
+// WithContext returns a Gcm bound to the context, passed along the chain after it, the receiver stays unchanged
+// Commands and query helpers get killed (with the git process group) once ctx is done
+// Use case: stop hung pull, fetch and push in service goroutines when requests end
+//
+// WithContext 返回绑定上下文的 Gcm，并传递给其后的链，接收者保持不变
+// ctx 结束后命令和查询辅助函数（连同 git 进程组）会被终止
+// 使用场景：在请求结束时停止服务协程中挂起的 pull、fetch 和 push
+func (G *Gcm) WithContext(ctx context.Context) *Gcm {
+	return G.withOptions(func(options *gcmOptions) { options.ctx = ctx })
+}
+
+// WithTimeout returns a Gcm with a per-command timeout on it and the chain after it, the receiver stays unchanged
+// Each command gets its own deadline, failing with context.DeadlineExceeded when exceeded
+// Use case: bound credential prompts, stalled network and lock contention waits
+//
+// WithTimeout 返回在其自身及其后的链上设置单命令超时的 Gcm，接收者保持不变
+// 每个命令拥有独立截止时间，超时时以 context.DeadlineExceeded 失败
+// 使用场景：限制凭据提示、网络停滞和锁竞争的等待时间
+func (G *Gcm) WithTimeout(timeout time.Duration) *Gcm {
+	return G.withOptions(func(options *gcmOptions) { options.timeout = timeout })
+}
+
+// WithRunner returns a Gcm whose commands, and those of the chain after it, run on the Runner
+// Replaces os/exec execution, debug logging and error propagation stay the same
+// Use case: unit-test code driving gitgo with a scripted fake runner and no git binary
+//
+// WithRunner 返回一个 Gcm，其命令及其后链中的命令由该 Runner 执行
+// 替换 os/exec 执行，调试日志和错误传播保持不变
+// 使用场景：使用脚本化的假执行器对驱动 gitgo 的代码进行单元测试，无需 git 程序
+func (G *Gcm) WithRunner(runner Runner) *Gcm {
+	return G.withOptions(func(options *gcmOptions) { options.runner = runner })
+}
+
+// WithDryRun returns a Gcm skipping mutating commands of it and the chain after it
+// Read-only queries like rev-parse, status and log still run, so chains keep their decisions
+// Skipped commands succeed with blank output, pair with WithRecorder to see the plan
+// Use case: preview ResetHard, Push and PushTags before running automation on production repos
+//
+// WithDryRun 返回一个 Gcm，跳过其自身及其后链中的修改性命令
+// rev-parse、status 和 log 等只读查询仍会运行，因此链的判断逻辑保持不变
+// 跳过的命令以空输出成功，配合 WithRecorder 查看执行计划
+// 使用场景：在生产仓库上运行自动化前预览 ResetHard、Push 和 PushTags
+func (G *Gcm) WithDryRun() *Gcm {
+	return G.withOptions(func(options *gcmOptions) { options.dryRun = true })
+}
+
+// WithRecorder returns a Gcm recording each command of it and the chain after it
+// Captures argv, working path and extra envs, with dry-run skipped commands included
+// Use case: export executed commands as a replayable shell script or JSON audit trail
+//
+// WithRecorder 返回一个 Gcm，记录其自身及其后链中的每个命令
+// 捕获 argv、工作路径和额外环境变量，包括 dry-run 跳过的命令
+// 使用场景：将执行的命令导出为可重放的 shell 脚本或 JSON 审计记录
+func (G *Gcm) WithRecorder(recorder *Recorder) *Gcm {
+	return G.withOptions(func(options *gcmOptions) { options.recorder = recorder })
+}
+
+// Use returns a Gcm with middlewares around each command of it and the chain after it
+// The first middleware added is the outermost, dry-run and recording happen inside them all
+// Use case: inject audit logging, metrics and policy checks around each git invocation
+//
+// Use 返回一个 Gcm，在其自身及其后链的每个命令周围添加中间件
+// 最先添加的中间件位于最外层，dry-run 和记录在所有中间件内部进行
+// 使用场景：在每次 git 调用周围注入审计日志、指标和策略检查
+func (G *Gcm) Use(middlewares ...Middleware) *Gcm {
+	return G.withOptions(func(options *gcmOptions) {
+		options.middlewares = slices.Concat(options.middlewares, middlewares)
+	})
+}
+
+// withOptions returns a copy of the Gcm with updated chain settings, the receiver stays unchanged
+// So chains derived from one shared base Gcm in different goroutines never see each other's settings
+//
+// withOptions 返回更新了链设置的 Gcm 副本，接收者保持不变
+// 因此在不同协程中从同一个共享基础 Gcm 派生的链不会看到彼此的设置
+func (G *Gcm) withOptions(update func(options *gcmOptions)) *Gcm {
+	options := G.options
+	update(&options)
+	return &Gcm{
+		execConfig: G.execConfig,
+		output:     G.output,
+		errorOnce:  G.errorOnce,
+		debugMode:  G.debugMode,
+		options:    options,
+	}
+}
+
 // ShowDebugMessage shows current execution state with tinted output
 // Success messages in green and problem messages in red to console
 // Use case: show debug output at specific points in chains
@@ -336,7 +464,7 @@ func (G *Gcm) When(condition func(*Gcm) bool, run func(*Gcm) *Gcm) *Gcm {
 // 使用场景：具有错误管理和验证的条件工作流
 func (G *Gcm) WhenThen(condition func(*Gcm) (bool, error), run func(*Gcm) *Gcm) *Gcm {
 	if success, err := condition(G); err != nil {
-		return newWaGcm(G.execConfig, []byte{}, err, G.debugMode)
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
 	} else if success {
 		return run(G)
 	}
@@ -1,10 +1,19 @@
 package gitgo_test
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
 	"github.com/yyle88/osexec"
+	"github.com/yyle88/rese"
 	"github.com/yyle88/runpath"
 )
 
@@ -39,3 +48,88 @@ func TestNewGcm(t *testing.T) {
 		ShowDebugMessage().
 		MustDone()
 }
+
+// TestGcm_WithContext tests chain and query helper behavior with a canceled context
+// Verifies that the error wraps context.Canceled and later commands get skipped
+//
+// TestGcm_WithContext 测试已取消上下文下的链和查询辅助函数行为
+// 验证错误包装了 context.Canceled 且后续命令被跳过
+func TestGcm_WithContext(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-context-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := gcm.WithContext(ctx).GetCurrentBranch()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+
+	err = gcm.WithContext(ctx).Status().Add().Reason()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+// TestGcm_WithTimeout tests per-command timeout with a hanging pre-commit hook
+// Verifies that the hook gets killed and the error wraps context.DeadlineExceeded
+//
+// TestGcm_WithTimeout 测试挂起的 pre-commit 钩子下的单命令超时
+// 验证钩子被终止且错误包装了 context.DeadlineExceeded
+func TestGcm_WithTimeout(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-timeout-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	hookPath := filepath.Join(tempDIR, ".git", "hooks", "pre-commit")
+	must.Done(os.WriteFile(hookPath, []byte("#!/bin/sh\nsleep 30\n"), 0755))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("v1"), 0644))
+
+	startTime := time.Now()
+	err := gcm.WithTimeout(500 * time.Millisecond).Add().Commit("hang").Reason()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, context.DeadlineExceeded))
+	require.Less(t, time.Since(startTime), 10*time.Second)
+
+	// Timeout applies to each command, so fast commands still succeed // 超时作用于每个命令，快速命令仍然成功
+	require.NoError(t, gcm.Status().Reason())
+}
+
+// TestGcm_WithContext_SharedBase tests chains derived from one shared base Gcm in parallel goroutines
+// Verifies each chain keeps its own context and the base stays unchanged
+//
+// TestGcm_WithContext_SharedBase 测试在并行协程中从同一个共享基础 Gcm 派生的链
+// 验证每条链保持各自的上下文且基础 Gcm 保持不变
+func TestGcm_WithContext_SharedBase(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-context-shared-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	base := gitgo.New(tempDIR)
+	base.Init().Done()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for idx, ctx := range []context.Context{canceledCtx, context.Background()} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[idx] = base.WithContext(ctx).WithTimeout(time.Minute).Status().Reason()
+		}()
+	}
+	wg.Wait()
+	require.True(t, errors.Is(errs[0], context.Canceled))
+	require.NoError(t, errs[1])
+
+	canceled := base.WithContext(canceledCtx)
+	live := base.WithContext(context.Background())
+	require.Error(t, canceled.Status().Reason())
+	require.NoError(t, live.Status().Reason())
+	require.NoError(t, base.Status().Reason())
+}
@@ -0,0 +1,191 @@
+package gitgo
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/osexistpath"
+)
+
+// RebaseBackend names the backend running an in-progress rebase
+//
+// RebaseBackend 表示运行进行中 rebase 的后端
+type RebaseBackend string
+
+const (
+	RebaseBackendMerge RebaseBackend = "merge" // State in .git/rebase-merge, the default backend // 状态位于 .git/rebase-merge，默认后端
+	RebaseBackendApply RebaseBackend = "apply" // State in .git/rebase-apply, the --apply backend // 状态位于 .git/rebase-apply，--apply 后端
+)
+
+// RebaseState describes the rebase in progress, InProgress is false when none is
+//
+// RebaseState 描述进行中的 rebase，没有时 InProgress 为 false
+type RebaseState struct {
+	InProgress    bool          // Rebase in progress // rebase 进行中
+	Backend       RebaseBackend // Backend running the rebase // 运行 rebase 的后端
+	HeadName      string        // Ref being rebased like "refs/heads/feature", "detached HEAD" when detached // 正在 rebase 的引用，如 "refs/heads/feature"，游离时为 "detached HEAD"
+	Onto          string        // Commit hash the commits get replayed onto // 提交被重放到的目标提交哈希
+	OrigHead      string        // Commit hash of the branch before the rebase // rebase 前分支的提交哈希
+	Step          int           // Current step, 1-based // 当前步骤，从 1 开始
+	Total         int           // Total steps // 总步骤数
+	StoppedCommit string        // Commit hash the rebase stopped at, blank when not stopped at a commit // rebase 停止处的提交哈希，未停在提交上时为空
+	Conflicts     []string      // Unmerged paths in the worktree // 工作区中的未合并路径
+}
+
+// Rebase replays commits of the current branch on top of upstream
+// Stops with a merge conflict error when commits do not apply, see RebaseState
+// Use case: keep feature branches current with main without merge commits
+//
+// Rebase 将当前分支的提交重放到 upstream 之上
+// 提交无法应用时以合并冲突错误停止，参见 RebaseState
+// 使用场景：使特性分支与 main 保持同步而不产生合并提交
+func (G *Gcm) Rebase(upstream string) *Gcm {
+	return G.do("git", "rebase", upstream)
+}
+
+// RebaseOnto replays commits of branch not in upstream on top of newbase
+// Blank branch means the current branch
+// Use case: move a branch created off a topic branch onto main
+//
+// RebaseOnto 将 branch 中不在 upstream 的提交重放到 newbase 之上
+// branch 为空表示当前分支
+// 使用场景：将基于主题分支创建的分支移动到 main 上
+func (G *Gcm) RebaseOnto(newbase, upstream, branch string) *Gcm {
+	if branch == "" {
+		return G.do("git", "rebase", "--onto", newbase, upstream)
+	}
+	return G.do("git", "rebase", "--onto", newbase, upstream, branch)
+}
+
+// RebaseContinue continues the rebase after conflicts got resolved and staged
+// Keeps the prepared commit messages without opening an editor
+// Use case: resume bots after resolving conflicts in code
+//
+// RebaseContinue 在冲突被解决并暂存后继续 rebase
+// 保留已准备的提交消息，不打开编辑器
+// 使用场景：在代码中解决冲突后恢复机器人流程
+func (G *Gcm) RebaseContinue() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "rebase", "--continue")
+}
+
+// RebaseAbort stops the rebase and restores the branch as it was before
+// Use case: give up on rebases with conflicts that bots cannot resolve
+//
+// RebaseAbort 停止 rebase 并将分支恢复到之前的状态
+// 使用场景：放弃机器人无法解决冲突的 rebase
+func (G *Gcm) RebaseAbort() *Gcm {
+	return G.do("git", "rebase", "--abort")
+}
+
+// RebaseSkip drops the commit the rebase stopped at and continues with the next one
+// Use case: skip commits whose changes upstream already has
+//
+// RebaseSkip 丢弃 rebase 停止处的提交并继续下一个
+// 使用场景：跳过其更改已存在于上游的提交
+func (G *Gcm) RebaseSkip() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "rebase", "--skip")
+}
+
+// RebaseState gets the state of the rebase in progress from the git dir
+// Reads rebase-merge and rebase-apply state files, conflicts come from the typed status
+// Use case: decide whether bots continue, skip or abort a stopped rebase
+//
+// RebaseState 从 git 目录获取进行中 rebase 的状态
+// 读取 rebase-merge 和 rebase-apply 状态文件，冲突来自类型化状态
+// 使用场景：决定机器人对停止的 rebase 继续、跳过还是中止
+func (G *Gcm) RebaseState() (*RebaseState, error) {
+	gitDIR, err := G.GetGitDIRAbsPath()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	state, err := readRebaseState(gitDIR)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !state.InProgress {
+		return state, nil
+	}
+	status, err := G.GetStatus()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, entry := range status.Entries {
+		if entry.Type == StatusUnmerged {
+			state.Conflicts = append(state.Conflicts, entry.Path)
+		}
+	}
+	return state, nil
+}
+
+// readRebaseState reads the state files of the rebase backend found in gitDIR
+// The apply backend doubles as git am state, only the "rebasing" marker makes it a rebase
+//
+// readRebaseState 读取 gitDIR 中找到的 rebase 后端的状态文件
+// apply 后端同时也是 git am 的状态，只有 "rebasing" 标记表示 rebase
+func readRebaseState(gitDIR string) (*RebaseState, error) {
+	mergeExists, err := osexistpath.IsRoot(filepath.Join(gitDIR, "rebase-merge"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	applyExists, err := osexistpath.IsFile(filepath.Join(gitDIR, "rebase-apply", "rebasing"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	var state = &RebaseState{}
+	var stepName, totalName, stoppedName string
+	var stateDIR string
+	switch {
+	case mergeExists:
+		stateDIR = filepath.Join(gitDIR, "rebase-merge")
+		state.Backend = RebaseBackendMerge
+		stepName, totalName, stoppedName = "msgnum", "end", "stopped-sha"
+	case applyExists:
+		stateDIR = filepath.Join(gitDIR, "rebase-apply")
+		state.Backend = RebaseBackendApply
+		stepName, totalName, stoppedName = "next", "last", "original-commit"
+	default:
+		return state, nil
+	}
+	state.InProgress = true
+	state.HeadName = readStateFile(stateDIR, "head-name")
+	state.Onto = readStateFile(stateDIR, "onto")
+	state.OrigHead = readStateFile(stateDIR, "orig-head")
+	state.StoppedCommit = readStateFile(stateDIR, stoppedName)
+	if state.Step, err = readStateNumber(stateDIR, stepName); err != nil {
+		return nil, erero.Wro(err)
+	}
+	if state.Total, err = readStateNumber(stateDIR, totalName); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return state, nil
+}
+
+// readStateFile reads one trimmed state file, blank when missing
+//
+// readStateFile 读取一个去除空白的状态文件，缺失时为空
+func readStateFile(stateDIR, name string) string {
+	data, err := os.ReadFile(filepath.Join(stateDIR, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readStateNumber reads one numeric state file, 0 when missing
+//
+// readStateNumber 读取一个数字状态文件，缺失时为 0
+func readStateNumber(stateDIR, name string) (int, error) {
+	text := readStateFile(stateDIR, name)
+	if text == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, erero.Wro(err)
+	}
+	return number, nil
+}
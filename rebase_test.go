@@ -0,0 +1,114 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/osexec"
+	"github.com/yyle88/rese"
+)
+
+// newRebaseTestRepo creates a repo where rebasing feature onto main conflicts on a.txt
+// Feature has two commits: "feature edit" touching a.txt and "feature add" adding b.txt
+//
+// newRebaseTestRepo 创建一个将 feature rebase 到 main 时在 a.txt 上冲突的仓库
+// feature 有两个提交："feature edit" 修改 a.txt，"feature add" 添加 b.txt
+func newRebaseTestRepo(t *testing.T) (*gitgo.Gcm, string) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-rebase-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("base\n"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("feature\n"), 0644))
+	gcm.Add().Commit("feature edit").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b.txt"), []byte("b\n"), 0644))
+	gcm.Add().Commit("feature add").Done()
+
+	gcm.Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("main\n"), 0644))
+	gcm.Add().Commit("main edit").Checkout("feature").Done()
+	return gcm, tempDIR
+}
+
+// TestGcm_Rebase tests stopping on conflicts, reading the state and continuing
+// Verifies step, total, stopped commit and conflicted paths of the merge backend
+//
+// TestGcm_Rebase 测试在冲突时停止、读取状态并继续
+// 验证 merge 后端的步骤、总数、停止提交和冲突路径
+func TestGcm_Rebase(t *testing.T) {
+	gcm, tempDIR := newRebaseTestRepo(t)
+	require.False(t, rese.P1(gcm.RebaseState()).InProgress)
+
+	err := gcm.Rebase("main").Reason()
+	require.Error(t, err)
+	require.True(t, gitgo.IsMergeConflict(err))
+
+	state := rese.P1(gcm.RebaseState())
+	require.True(t, state.InProgress)
+	require.Equal(t, gitgo.RebaseBackendMerge, state.Backend)
+	require.Equal(t, "refs/heads/feature", state.HeadName)
+	require.Equal(t, rese.C1(gcm.GetCommitHash("main")), state.Onto)
+	require.Equal(t, 1, state.Step)
+	require.Equal(t, 2, state.Total)
+	require.Equal(t, "feature edit", rese.C1(gcm.GetCommitMessage(state.StoppedCommit)))
+	require.Equal(t, []string{"a.txt"}, state.Conflicts)
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("resolved\n"), 0644))
+	gcm.Add().RebaseContinue().Done()
+	require.False(t, rese.P1(gcm.RebaseState()).InProgress)
+
+	commits := rese.V1(gcm.Log(gitgo.LogOptions{}))
+	require.Equal(t, []string{"feature add", "feature edit", "main edit", "initial"}, []string{
+		commits[0].Subject, commits[1].Subject, commits[2].Subject, commits[3].Subject,
+	})
+}
+
+// TestGcm_RebaseAbortSkip tests aborting and skipping a stopped rebase
+//
+// TestGcm_RebaseAbortSkip 测试中止和跳过停止的 rebase
+func TestGcm_RebaseAbortSkip(t *testing.T) {
+	gcm, _ := newRebaseTestRepo(t)
+	featureHash := rese.C1(gcm.GetCurrentCommitHash())
+
+	require.Error(t, gcm.Rebase("main").Reason())
+	gcm.RebaseAbort().Done()
+	require.False(t, rese.P1(gcm.RebaseState()).InProgress)
+	require.Equal(t, featureHash, rese.C1(gcm.GetCurrentCommitHash()))
+
+	require.Error(t, gcm.Rebase("main").Reason())
+	gcm.RebaseSkip().Done()
+	require.False(t, rese.P1(gcm.RebaseState()).InProgress)
+	require.Equal(t, 3, rese.C1(gcm.GetCommitCount()))
+	require.Equal(t, "feature add", rese.C1(gcm.GetCommitMessage("HEAD")))
+}
+
+// TestGcm_RebaseOnto tests moving commits onto a new base and the apply backend state
+//
+// TestGcm_RebaseOnto 测试将提交移动到新基点以及 apply 后端的状态
+func TestGcm_RebaseOnto(t *testing.T) {
+	gcm, _ := newRebaseTestRepo(t)
+
+	gcm.RebaseOnto("main", "feature~1", "").Done()
+	require.Equal(t, 3, rese.C1(gcm.GetCommitCount()))
+	require.Equal(t, "main edit", rese.C1(gcm.GetCommitMessage("HEAD~1")))
+
+	applyGcm, applyDIR := newRebaseTestRepo(t)
+	_, err := osexec.ExecInPath(applyDIR, "git", "rebase", "--apply", "main")
+	require.Error(t, err)
+	state := rese.P1(applyGcm.RebaseState())
+	require.True(t, state.InProgress)
+	require.Equal(t, gitgo.RebaseBackendApply, state.Backend)
+	require.Equal(t, "refs/heads/feature", state.HeadName)
+	require.Equal(t, 1, state.Step)
+	require.Equal(t, 2, state.Total)
+	require.Equal(t, []string{"a.txt"}, state.Conflicts)
+	applyGcm.RebaseAbort().Done()
+}
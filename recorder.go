@@ -0,0 +1,149 @@
+package gitgo
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// Record is one command captured by a Recorder
+// Args holds the argv of the process, shell wrapped commands start with the shell
+//
+// Record 是 Recorder 捕获的一个命令
+// Args 保存进程的 argv，shell 包装的命令以 shell 开头
+type Record struct {
+	Args     []string      `json:"args"`           // Argv of the process // 进程的 argv
+	Path     string        `json:"path"`           // Working path // 工作路径
+	Envs     []string      `json:"envs,omitempty"` // Extra environment variables // 额外环境变量
+	ReadOnly bool          `json:"readOnly"`       // Command only reads the repo // 命令仅读取仓库
+	DryRun   bool          `json:"dryRun"`         // Skipped by dry-run mode // 被 dry-run 模式跳过
+	ExitCode int           `json:"exitCode"`       // Exit code, -1 when not exited normally // 退出码，非正常退出时为 -1
+	Duration time.Duration `json:"duration"`       // Run duration in nanoseconds // 运行时长（纳秒）
+}
+
+// Recorder collects the transcript of commands run through Gcm chains and query helpers
+// Safe to share between goroutines and between chains on different repos
+//
+// Recorder 收集通过 Gcm 链和查询辅助函数运行的命令记录
+// 可在协程间以及不同仓库的链之间共享
+type Recorder struct {
+	mutex   sync.Mutex
+	records []Record
+}
+
+// NewRecorder creates a blank Recorder
+//
+// NewRecorder 创建空的 Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Records returns the captured commands in execution sequence
+//
+// Records 按执行顺序返回捕获的命令
+func (r *Recorder) Records() []Record {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return slices.Clone(r.records)
+}
+
+// Reset drops the captured commands
+//
+// Reset 清除已捕获的命令
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.records = nil
+}
+
+// JSON exports the captured commands as an indented JSON array
+//
+// JSON 将捕获的命令导出为缩进的 JSON 数组
+func (r *Recorder) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(r.Records(), "", "  ")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return data, nil
+}
+
+// ShellScript exports the captured commands as a replayable shell script
+// Each command runs in a subshell with its working path and extra envs, stopping at the first failure
+// Read-only queries stay in the script as comments, since replaying them changes nothing
+//
+// ShellScript 将捕获的命令导出为可重放的 shell 脚本
+// 每个命令在带有其工作路径和额外环境变量的子 shell 中运行，遇到首个失败即停止
+// 只读查询以注释形式保留在脚本中，因为重放它们不会改变任何东西
+func (r *Recorder) ShellScript() string {
+	var script strings.Builder
+	script.WriteString("#!/usr/bin/env bash\n")
+	script.WriteString("set -euo pipefail\n")
+	for _, record := range r.Records() {
+		if record.ReadOnly {
+			script.WriteString("# ")
+		}
+		script.WriteString(record.shellLine())
+		script.WriteString("\n")
+	}
+	return script.String()
+}
+
+// shellLine renders the record as one quoted shell line
+//
+// shellLine 将记录渲染为一行带引号的 shell 命令
+func (record *Record) shellLine() string {
+	var parts []string
+	if record.Path != "" {
+		parts = append(parts, "cd", shellQuote(record.Path), "&&")
+	}
+	if len(record.Envs) > 0 {
+		parts = append(parts, "env")
+		for _, env := range record.Envs {
+			parts = append(parts, shellQuote(env))
+		}
+	}
+	for _, arg := range record.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// shellSafe matches words needing no quotes in shell scripts
+//
+// shellSafe 匹配在 shell 脚本中无需引号的单词
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:=@%+,-]+$`)
+
+// shellQuote quotes the word with single quotes when it has shell special characters
+//
+// shellQuote 当单词包含 shell 特殊字符时使用单引号引用
+func shellQuote(word string) string {
+	if shellSafe.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// record appends the command with its outcome
+//
+// record 追加命令及其结果
+func (r *Recorder) record(command *Command, readOnly bool, dryRun bool, exitCode int, duration time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.records = append(r.records, Record{
+		Args:     execArgv(command),
+		Path:     command.Path,
+		Envs:     slices.Clone(command.Envs),
+		ReadOnly: readOnly,
+		DryRun:   dryRun,
+		ExitCode: exitCode,
+		Duration: duration,
+	})
+}
@@ -0,0 +1,72 @@
+package gitgo_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/osexec"
+	"github.com/yyle88/rese"
+)
+
+// TestRecorder_ShellScript tests replaying a dry-run plan exported as a shell script
+// Verifies the dry-run leaves the repo untouched and the script performs the commit
+//
+// TestRecorder_ShellScript 测试重放导出为 shell 脚本的 dry-run 计划
+// 验证 dry-run 不改动仓库且脚本能完成提交
+func TestRecorder_ShellScript(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-recorder-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+
+	recorder := gitgo.NewRecorder()
+	gitgo.New(tempDIR).WithDryRun().WithRecorder(recorder).Add().Commit("it's planned").Done()
+	require.True(t, rese.P1(gcm.GetStatus()).Branch.IsInitial())
+
+	script := recorder.ShellScript()
+	require.Contains(t, script, "'it'\\''s planned'")
+	scriptPath := filepath.Join(t.TempDir(), "replay.sh")
+	must.Done(os.WriteFile(scriptPath, []byte(script), 0755))
+	rese.V1(osexec.Exec("bash", scriptPath))
+
+	require.Equal(t, "it's planned", rese.C1(gcm.GetCommitMessage("HEAD")))
+}
+
+// TestRecorder_JSON tests exporting executed commands with outcomes as JSON
+//
+// TestRecorder_JSON 测试将执行的命令及结果导出为 JSON
+func TestRecorder_JSON(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-recorder-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	recorder := gitgo.NewRecorder()
+	gcm := gitgo.New(tempDIR).WithRecorder(recorder)
+	gcm.Init().Done()
+	_, err := gcm.GetCurrentCommitHash()
+	require.Error(t, err)
+
+	var records []gitgo.Record
+	must.Done(json.Unmarshal(rese.V1(recorder.JSON()), &records))
+	require.Len(t, records, 2)
+	require.Equal(t, []string{"git", "init"}, records[0].Args)
+	require.False(t, records[0].ReadOnly)
+	require.Equal(t, 0, records[0].ExitCode)
+	require.Equal(t, []string{"git", "rev-parse", "HEAD"}, records[1].Args)
+	require.True(t, records[1].ReadOnly)
+	require.Equal(t, 128, records[1].ExitCode)
+	require.Equal(t, tempDIR, records[1].Path)
+
+	recorder.Reset()
+	require.Empty(t, recorder.Records())
+}
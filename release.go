@@ -0,0 +1,190 @@
+package gitgo
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/yyle88/erero"
+)
+
+// ReleaseOptions configures Release
+//
+// ReleaseOptions 配置 Release
+type ReleaseOptions struct {
+	TagPrefix         string                                                    // Monorepo component tag prefix like "auth/" // monorepo 组件标签前缀，如 "auth/"
+	Bump              VersionBump                                               // Version bump, blank means BumpPatch // 版本递增方式，为空表示 BumpPatch
+	Message           string                                                    // Tag message, blank means "Release <tag>" // 标签消息，为空表示 "Release <tag>"
+	VersionFile       string                                                    // File to update and commit, relative to the repo top, blank means none // 要更新并提交的文件，相对于仓库顶层，为空表示不更新
+	UpdateVersionFile func(content []byte, version *TagVersion) ([]byte, error) // Rewrites the version file, nil means replacing it with the bare version like "1.2.3" // 重写版本文件，nil 表示替换为纯版本号，如 "1.2.3"
+	CommitMessage     string                                                    // Version file commit message, blank means "chore(release): <tag>" // 版本文件提交消息，为空表示 "chore(release): <tag>"
+}
+
+// ReleaseResult describes a pushed release
+//
+// ReleaseResult 描述一次已推送的发布
+type ReleaseResult struct {
+	Version *TagVersion // Released version // 发布的版本
+	Tag     string      // Tag name like "auth/v1.2.3" // 标签名称，如 "auth/v1.2.3"
+	Branch  string      // Released local branch // 发布的本地分支
+	Remote  string      // Remote of the branch upstream // 分支上游所在的远程
+	Commit  string      // Tagged commit hash // 被标记的提交哈希
+}
+
+// Release cuts the next version of the current branch and pushes it
+// Checks the tree is clean, untracked files included, and the branch is not behind its upstream after a fetch,
+// then computes the next tag, updates and commits the version file when set and creates an annotated tag
+// Pushes the branch and tag with --atomic, so the remote gets both or neither
+// When the push fails, the local tag and version commit get rolled back
+// Use case: replace release scripts chaining GetStatus, GetLatestTag, Tag, PushTag and Push
+//
+// Release 发布当前分支的下一个版本并推送
+// 检查工作区干净（包括未跟踪文件），并在 fetch 后检查分支没有落后于上游，
+// 然后计算下一个标签，设置了版本文件时更新并提交它，并创建附注标签
+// 使用 --atomic 推送分支和标签，远程要么两者都收到，要么都不收到
+// 推送失败时回滚本地标签和版本提交
+// 使用场景：替代串联 GetStatus、GetLatestTag、Tag、PushTag 和 Push 的发布脚本
+func (G *Gcm) Release(opts ReleaseOptions) (*ReleaseResult, error) {
+	status, err := G.GetStatus()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !status.IsClean() {
+		return nil, erero.New("release needs a clean work tree, untracked files included")
+	}
+	branch, err := G.GetCurrentBranch()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if branch == "HEAD" {
+		return nil, erero.New("release needs a branch, HEAD is detached")
+	}
+	remote, err := G.ConfigGet("branch." + branch + ".remote")
+	if err != nil {
+		return nil, erero.Wrapf(err, "branch %s has no upstream", branch)
+	}
+	mergeRef, err := G.ConfigGet("branch." + branch + ".merge")
+	if err != nil {
+		return nil, erero.Wrapf(err, "branch %s has no upstream", branch)
+	}
+	if err := G.Fetch(remote).Reason(); err != nil {
+		return nil, erero.Wro(err)
+	}
+	divergence, err := G.Divergence(branch)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if divergence.State == DivergenceBehind || divergence.State == DivergenceDiverged {
+		return nil, erero.Errorf("branch %s is %s with %s, %d behind", branch, divergence.State, divergence.Upstream, divergence.Behind)
+	}
+
+	bump := opts.Bump
+	if bump == "" {
+		bump = BumpPatch
+	}
+	next, err := G.NextVersion(opts.TagPrefix, bump)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	tag := next.String()
+	prevHead, err := G.GetCurrentCommitHash()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	committed := false
+	if opts.VersionFile != "" {
+		if err := G.commitVersionFile(opts, next); err != nil {
+			return nil, erero.Wro(err)
+		}
+		committed = true
+	}
+	message := opts.Message
+	if message == "" {
+		message = "Release " + tag
+	}
+	if err := G.TagAnnotated(tag, message, "").Reason(); err != nil {
+		return nil, erero.Wro(erero.Join(err, G.rollbackRelease("", prevHead, committed)))
+	}
+	if err := G.do("git", "push", "--atomic", remote, "refs/heads/"+branch+":"+mergeRef, "refs/tags/"+tag).Reason(); err != nil {
+		return nil, erero.Wro(erero.Join(err, G.rollbackRelease(tag, prevHead, committed)))
+	}
+	commit, err := G.GetCurrentCommitHash()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return &ReleaseResult{
+		Version: next,
+		Tag:     tag,
+		Branch:  branch,
+		Remote:  remote,
+		Commit:  commit,
+	}, nil
+}
+
+// commitVersionFile writes the version into the version file and commits only that file
+// Dry-run leaves the file alone, so only the skipped add and commit get recorded
+//
+// commitVersionFile 将版本写入版本文件并只提交该文件
+// dry-run 时不动该文件，只记录被跳过的 add 和 commit
+func (G *Gcm) commitVersionFile(opts ReleaseOptions, version *TagVersion) error {
+	topPath, err := G.GetTopPath()
+	if err != nil {
+		return erero.Wro(err)
+	}
+	commitMessage := opts.CommitMessage
+	if commitMessage == "" {
+		commitMessage = "chore(release): " + version.String()
+	}
+	top := G.newPathGcm(topPath)
+	if G.options.dryRun {
+		return top.do("git", "add", "--", opts.VersionFile).do("git", "commit", "-m", commitMessage, "--", opts.VersionFile).Reason()
+	}
+	path := filepath.Join(topPath, opts.VersionFile)
+	original, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return erero.Wro(err)
+	}
+	var content []byte
+	if opts.UpdateVersionFile != nil {
+		if content, err = opts.UpdateVersionFile(original, version); err != nil {
+			return erero.Wro(err)
+		}
+	} else {
+		bare := *version
+		bare.Prefix, bare.V = "", false
+		content = []byte(bare.String() + "\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return erero.Wro(err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return erero.Wro(err)
+	}
+	if err := top.do("git", "add", "--", opts.VersionFile).do("git", "commit", "-m", commitMessage, "--", opts.VersionFile).Reason(); err != nil {
+		// Unstage and put back the original content, leaving the tree as before // 取消暂存并恢复原始内容，使工作区保持原样
+		restoreErr := top.do("git", "reset", "-q", "--", opts.VersionFile).Reason()
+		if existed {
+			restoreErr = erero.Join(restoreErr, os.WriteFile(path, original, 0644))
+		} else {
+			restoreErr = erero.Join(restoreErr, os.Remove(path))
+		}
+		return erero.Wro(erero.Join(err, restoreErr))
+	}
+	return nil
+}
+
+// rollbackRelease deletes the release tag when set and resets to the previous head when committed
+// Uses reset --keep, the tree was clean before the release, so only the version commit goes away
+//
+// rollbackRelease 在设置了 tag 时删除发布标签，并在已提交时重置到之前的 head
+// 使用 reset --keep，发布前工作区是干净的，因此只会移除版本提交
+func (G *Gcm) rollbackRelease(tag, prevHead string, committed bool) error {
+	var errs []error
+	if tag != "" {
+		errs = append(errs, G.TagDelete(tag).Reason())
+	}
+	if committed {
+		errs = append(errs, G.do("git", "reset", "--keep", prevHead).Reason())
+	}
+	return erero.Joins(errs)
+}
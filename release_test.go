@@ -0,0 +1,139 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Release tests releasing with and without a version file into a bare remote
+// Verifies the clean tree and upstream checks, and the rollback after a rejected push
+//
+// TestGcm_Release 测试带和不带版本文件地发布到裸远程仓库
+// 验证干净工作区和上游检查，以及推送被拒绝后的回滚
+func TestGcm_Release(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-release-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	repoPath := filepath.Join(tempDIR, "repo")
+	must.Done(os.MkdirAll(repoPath, 0755))
+
+	gcm := gitgo.New(repoPath)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Tag("v1.0.0").Done()
+
+	_, err := gcm.Release(gitgo.ReleaseOptions{})
+	require.Error(t, err) // No upstream yet // 还没有上游
+
+	remotePath := filepath.Join(tempDIR, "remote.git")
+	rese.P1(gitgo.Clone(repoPath, remotePath, gitgo.CloneOptions{Bare: true}))
+	gcm.RemoteAdd("origin", remotePath).PushWithUpstream("main").Done()
+	remote := gitgo.New(remotePath)
+
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("dirty"), 0644))
+	_, err = gcm.Release(gitgo.ReleaseOptions{})
+	require.Error(t, err)
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+
+	// An untracked file would get lost by the release, so it blocks it too
+	// 未跟踪文件会在发布中丢失，因此同样阻止发布
+	must.Done(os.WriteFile(filepath.Join(repoPath, "notes.txt"), []byte("notes"), 0644))
+	_, err = gcm.Release(gitgo.ReleaseOptions{})
+	require.Error(t, err)
+	require.False(t, rese.V1(gcm.TagExists("v1.0.1")))
+	must.Done(os.Remove(filepath.Join(repoPath, "notes.txt")))
+
+	must.Done(os.WriteFile(filepath.Join(repoPath, "b.txt"), []byte("b"), 0644))
+	gcm.Add().Commit("feat: add b").Done()
+	result := rese.P1(gcm.Release(gitgo.ReleaseOptions{Bump: gitgo.BumpMinor}))
+	require.Equal(t, "v1.1.0", result.Tag)
+	require.Equal(t, "main", result.Branch)
+	require.Equal(t, "origin", result.Remote)
+	require.Equal(t, rese.V1(gcm.GetCurrentCommitHash()), result.Commit)
+	require.Equal(t, result.Commit, rese.V1(remote.GetCommitHash("main")))
+	require.Equal(t, result.Commit, rese.V1(remote.GetCommitHash("v1.1.0^{commit}")))
+
+	result = rese.P1(gcm.Release(gitgo.ReleaseOptions{TagPrefix: "auth/", VersionFile: "auth/VERSION", Message: "auth release"}))
+	require.Equal(t, "auth/v0.0.1", result.Tag)
+	require.Equal(t, "0.0.1\n", string(rese.V1(os.ReadFile(filepath.Join(repoPath, "auth", "VERSION")))))
+	require.Equal(t, "chore(release): auth/v0.0.1", rese.C1(gcm.GetCommitMessage("HEAD")))
+	require.Equal(t, result.Commit, rese.V1(remote.GetCommitHash("main")))
+	var message string
+	for _, info := range rese.V1(remote.ListTagInfos()) {
+		if info.Name == "auth/v0.0.1" {
+			message = info.Message
+		}
+	}
+	require.Equal(t, "auth release", message)
+
+	// Reject pushes on the remote, the release must leave neither tag nor commit behind
+	// 在远程拒绝推送，发布不能留下标签或提交
+	hookPath := filepath.Join(remotePath, "hooks", "pre-receive")
+	must.Done(os.WriteFile(hookPath, []byte("#!/bin/sh\nexit 1\n"), 0755))
+	headBefore := rese.V1(gcm.GetCurrentCommitHash())
+	_, err = gcm.Release(gitgo.ReleaseOptions{VersionFile: "VERSION", Bump: gitgo.BumpMajor})
+	require.Error(t, err)
+	require.False(t, rese.V1(gcm.TagExists("v2.0.0")))
+	require.False(t, rese.V1(remote.TagExists("v2.0.0")))
+	require.Equal(t, headBefore, rese.V1(gcm.GetCurrentCommitHash()))
+	require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+	must.Done(os.Remove(hookPath))
+
+	// Another clone pushes first, the branch falls behind its upstream
+	// 另一个克隆先推送，分支落后于上游
+	other := rese.P1(gitgo.Clone(remotePath, filepath.Join(tempDIR, "other"), gitgo.CloneOptions{}))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "other", "c.txt"), []byte("c"), 0644))
+	other.Add().Commit("fix: add c").Push().Done()
+	_, err = gcm.Release(gitgo.ReleaseOptions{})
+	require.Error(t, err)
+	require.False(t, rese.V1(gcm.TagExists("v1.1.1")))
+}
+
+// TestGcm_Release_DryRun tests a dry-run release with a version file in a fresh clone
+// Verifies nothing gets written, tagged or pushed, so a real release still runs next
+//
+// TestGcm_Release_DryRun 测试在新克隆中带版本文件的 dry-run 发布
+// 验证不会写入、打标签或推送任何内容，因此之后仍可进行真实发布
+func TestGcm_Release_DryRun(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-release-dryrun-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	sourcePath := filepath.Join(tempDIR, "source")
+	must.Done(os.MkdirAll(sourcePath, 0755))
+	source := gitgo.New(sourcePath)
+	source.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(sourcePath, "a.txt"), []byte("a"), 0644))
+	source.Add().Commit("initial").Done()
+	remotePath := filepath.Join(tempDIR, "remote.git")
+	rese.P1(gitgo.Clone(sourcePath, remotePath, gitgo.CloneOptions{Bare: true}))
+	gcm := rese.P1(gitgo.Clone(remotePath, filepath.Join(tempDIR, "work"), gitgo.CloneOptions{}))
+
+	recorder := gitgo.NewRecorder()
+	result := rese.P1(gcm.WithDryRun().WithRecorder(recorder).Release(gitgo.ReleaseOptions{VersionFile: "VERSION"}))
+	require.Equal(t, "v0.0.1", result.Tag)
+	require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+	require.NoFileExists(t, filepath.Join(tempDIR, "work", "VERSION"))
+	require.False(t, rese.V1(gcm.TagExists("v0.0.1")))
+	var skipped []string
+	for _, record := range recorder.Records() {
+		if record.DryRun {
+			skipped = append(skipped, record.Args[1])
+		}
+	}
+	require.Contains(t, skipped, "add")
+	require.Contains(t, skipped, "commit")
+	require.Contains(t, skipped, "push")
+
+	result = rese.P1(gcm.Release(gitgo.ReleaseOptions{VersionFile: "VERSION"}))
+	require.Equal(t, "v0.0.1", result.Tag)
+	require.Equal(t, "0.0.1\n", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "work", "VERSION")))))
+	require.True(t, rese.V1(gitgo.New(remotePath).TagExists("v0.0.1")))
+}
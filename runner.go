@@ -0,0 +1,191 @@
+package gitgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cancelWaitDelay bounds how long Wait blocks on output pipes once the process is killed
+// Prevents grandchild processes holding stdout from stalling cancellation
+//
+// cancelWaitDelay 限制进程被终止后 Wait 在输出管道上阻塞的时长
+// 防止持有 stdout 的孙进程拖延取消操作
+const cancelWaitDelay = 3 * time.Second
+
+// Command describes one command that Gcm asks the Runner to execute
+// Built from the execution configuration, the Runner needs no osexec knowledge
+//
+// Command 描述 Gcm 请求 Runner 执行的一个命令
+// 由执行配置构建，Runner 无需了解 osexec
+type Command struct {
+	Name      string    // Program name like "git" // 程序名，如 "git"
+	Args      []string  // Arguments after the program name // 程序名之后的参数
+	Path      string    // Working path // 工作路径
+	Envs      []string  // Extra environment variables appended to os.Environ() // 追加到 os.Environ() 的额外环境变量
+	ShellType string    // Shell to wrap the command, blank runs directly // 包装命令的 shell，为空时直接运行
+	ShellFlag string    // Shell flag like "-c" // Shell 参数，如 "-c"
+	Stdout    io.Writer // When set, stdout streams here and stays out of Result // 设置时 stdout 流向此处且不计入 Result
+}
+
+// Argv returns the program name followed by the arguments
+//
+// Argv 返回程序名及其后的参数
+func (c *Command) Argv() []string {
+	return append([]string{c.Name}, c.Args...)
+}
+
+// String renders the command as a shell-like line for logs
+//
+// String 将命令渲染为类 shell 的行用于日志
+func (c *Command) String() string {
+	var parts []string
+	if c.Path != "" {
+		parts = append(parts, "cd", c.Path, "&&")
+	}
+	parts = append(parts, c.Envs...)
+	if c.ShellType != "" {
+		parts = append(parts, c.ShellType, c.ShellFlag)
+	}
+	parts = append(parts, c.Argv()...)
+	return strings.Join(parts, " ")
+}
+
+// Result holds the captured outcome of one command
+//
+// Result 保存一次命令的捕获结果
+type Result struct {
+	Output   []byte // Interleaved stdout and stderr // 交错的 stdout 和 stderr
+	Stdout   []byte // Standard output // 标准输出
+	Stderr   []byte // Standard error // 标准错误
+	ExitCode int    // Exit code, -1 when not exited normally // 退出码，非正常退出时为 -1
+}
+
+// Runner executes commands on behalf of Gcm chains and query helpers
+// Run returns a non-nil error when the command did not exit with 0, ExitCode tells the code
+// Implementations must honor ctx cancellation and must return a non-nil Result
+//
+// Runner 代表 Gcm 链和查询辅助函数执行命令
+// 命令未以 0 退出时 Run 返回非 nil 错误，ExitCode 给出退出码
+// 实现必须响应 ctx 取消，且必须返回非 nil 的 Result
+type Runner interface {
+	Run(ctx context.Context, command *Command) (*Result, error)
+}
+
+// RunnerFunc adapts a function to the Runner interface
+//
+// RunnerFunc 将函数适配为 Runner 接口
+type RunnerFunc func(ctx context.Context, command *Command) (*Result, error)
+
+// Run calls f(ctx, command)
+//
+// Run 调用 f(ctx, command)
+func (f RunnerFunc) Run(ctx context.Context, command *Command) (*Result, error) {
+	return f(ctx, command)
+}
+
+// ExecRunner is the default Runner running commands with os/exec
+// Puts the process in its own group when ctx can be canceled, so cancellation kills the whole tree
+//
+// ExecRunner 是使用 os/exec 运行命令的默认 Runner
+// 当 ctx 可取消时将进程放入独立进程组，以便取消时终止整个进程树
+type ExecRunner struct{}
+
+// NewExecRunner creates the default os/exec based Runner
+//
+// NewExecRunner 创建基于 os/exec 的默认 Runner
+func NewExecRunner() *ExecRunner {
+	return &ExecRunner{}
+}
+
+// Run executes the command and captures stdout, stderr and the interleaved output
+//
+// Run 执行命令并捕获 stdout、stderr 和交错输出
+func (r *ExecRunner) Run(ctx context.Context, command *Command) (*Result, error) {
+	result := &Result{ExitCode: -1}
+
+	cmd := prepareCommand(ctx, command)
+	var stdout, stderr bytes.Buffer
+	var output = &syncBuffer{}
+	if command.Stdout != nil {
+		cmd.Stdout = command.Stdout
+	} else {
+		cmd.Stdout = io.MultiWriter(&stdout, output)
+	}
+	cmd.Stderr = io.MultiWriter(&stderr, output)
+
+	err := cmd.Run()
+	result.Output = output.Bytes()
+	result.Stdout = stdout.Bytes()
+	result.Stderr = stderr.Bytes()
+	if err != nil {
+		if ext := new(exec.ExitError); errors.As(err, &ext) {
+			result.ExitCode = ext.ExitCode()
+		}
+		return result, err
+	}
+	result.ExitCode = 0
+	return result, nil
+}
+
+// prepareCommand creates the exec.Cmd honoring path, envs and shell settings of the command
+//
+// prepareCommand 根据命令的路径、环境变量和 shell 设置创建 exec.Cmd
+func prepareCommand(ctx context.Context, command *Command) *exec.Cmd {
+	argv := execArgv(command)
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = command.Path
+	if len(command.Envs) > 0 {
+		cmd.Env = append(os.Environ(), command.Envs...)
+	}
+	if ctx.Done() != nil {
+		setProcessGroupCancel(cmd)
+		cmd.WaitDelay = cancelWaitDelay
+	}
+	return cmd
+}
+
+// execArgv returns the argv of the process, wrapping the command line with the shell when set
+//
+// execArgv 返回进程的 argv，设置 shell 时用 shell 包装命令行
+func execArgv(command *Command) []string {
+	if command.ShellType != "" {
+		return []string{command.ShellType, command.ShellFlag, strings.Join(command.Argv(), " ")}
+	}
+	return command.Argv()
+}
+
+// syncBuffer is a bytes.Buffer safe to write from stdout and stderr copy goroutines
+// Keeps the interleaved combined output that Output() exposes
+//
+// syncBuffer 是可由 stdout 和 stderr 复制协程并发写入的 bytes.Buffer
+// 保留 Output() 暴露的交错合并输出
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+// Write appends p under the lock
+//
+// Write 在锁内追加 p
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+// Bytes returns the collected bytes
+//
+// Bytes 返回收集到的字节
+func (b *syncBuffer) Bytes() []byte {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Bytes()
+}
@@ -0,0 +1,56 @@
+package gitgo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_WithRunner tests commands going through a custom Runner
+// Verifies the Runner sees the path and argv and can delegate to ExecRunner
+//
+// TestGcm_WithRunner 测试命令经过自定义 Runner
+// 验证 Runner 能看到路径和 argv，并能委托给 ExecRunner
+func TestGcm_WithRunner(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-runner-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	var commands []string
+	runner := gitgo.RunnerFunc(func(ctx context.Context, command *gitgo.Command) (*gitgo.Result, error) {
+		require.Equal(t, tempDIR, command.Path)
+		commands = append(commands, command.String())
+		return gitgo.NewExecRunner().Run(ctx, command)
+	})
+
+	gcm := gitgo.New(tempDIR).WithRunner(runner)
+	gcm.Init().Done()
+	require.True(t, rese.V1(gcm.IsInsideWorkTree()))
+	require.Equal(t, []string{
+		"cd " + tempDIR + " && git init",
+		"cd " + tempDIR + " && git rev-parse --is-inside-work-tree",
+	}, commands)
+}
+
+// TestExecRunner_Run tests the default Runner capturing outputs and exit codes
+//
+// TestExecRunner_Run 测试默认 Runner 捕获输出和退出码
+func TestExecRunner_Run(t *testing.T) {
+	runner := gitgo.NewExecRunner()
+
+	result, err := runner.Run(context.Background(), &gitgo.Command{Name: "git", Args: []string{"--version"}})
+	require.NoError(t, err)
+	require.Equal(t, 0, result.ExitCode)
+	require.Contains(t, string(result.Stdout), "git version")
+
+	result, err = runner.Run(context.Background(), &gitgo.Command{Name: "git", Args: []string{"rev-parse"}, Path: os.TempDir()})
+	require.Error(t, err)
+	require.Equal(t, 128, result.ExitCode)
+	require.Contains(t, string(result.Stderr), "not a git repository")
+}
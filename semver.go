@@ -0,0 +1,287 @@
+package gitgo
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// semVerRegexp matches SemVer 2.0 versions, following the regexp suggested by semver.org
+//
+// semVerRegexp 匹配 SemVer 2.0 版本，采用 semver.org 建议的正则表达式
+var semVerRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// DefaultPrereleaseID is the prerelease identifier NextVersion starts prereleases with
+//
+// DefaultPrereleaseID 是 NextVersion 开始预发布版本时使用的预发布标识符
+const DefaultPrereleaseID = "rc"
+
+// VersionBump names the version part NextVersion increments
+//
+// VersionBump 表示 NextVersion 递增的版本部分
+type VersionBump string
+
+const (
+	BumpMajor      VersionBump = "major"      // 1.2.3 -> 2.0.0, 2.0.0-rc.1 -> 2.0.0 // 1.2.3 -> 2.0.0，2.0.0-rc.1 -> 2.0.0
+	BumpMinor      VersionBump = "minor"      // 1.2.3 -> 1.3.0, 1.3.0-rc.1 -> 1.3.0 // 1.2.3 -> 1.3.0，1.3.0-rc.1 -> 1.3.0
+	BumpPatch      VersionBump = "patch"      // 1.2.3 -> 1.2.4, 1.2.4-rc.1 -> 1.2.4 // 1.2.3 -> 1.2.4，1.2.4-rc.1 -> 1.2.4
+	BumpPrerelease VersionBump = "prerelease" // 1.2.3 -> 1.2.4-rc.1, 1.2.4-rc.1 -> 1.2.4-rc.2 // 1.2.3 -> 1.2.4-rc.1，1.2.4-rc.1 -> 1.2.4-rc.2
+)
+
+// TagVersion is a tag parsed as prefix, optional "v" and a SemVer 2.0 version
+//
+// TagVersion 是解析为前缀、可选 "v" 和 SemVer 2.0 版本的标签
+type TagVersion struct {
+	Prefix     string // Tag prefix like "auth/", blank when none // 标签前缀，如 "auth/"，没有时为空
+	V          bool   // Version starts with "v" // 版本以 "v" 开头
+	Major      uint64 // Major version // 主版本号
+	Minor      uint64 // Minor version // 次版本号
+	Patch      uint64 // Patch version // 修订号
+	Prerelease string // Dot separated prerelease identifiers like "rc.1", blank on releases // 以点分隔的预发布标识符，如 "rc.1"，正式版本为空
+	Build      string // Dot separated build metadata, ignored in precedence // 以点分隔的构建元数据，不参与优先级比较
+}
+
+// ParseTagVersion parses the tag as prefix, optional "v" and a SemVer 2.0 version
+// Use case: read versions of component tags like "auth/v1.2.3"
+//
+// ParseTagVersion 将标签解析为前缀、可选 "v" 和 SemVer 2.0 版本
+// 使用场景：读取组件标签的版本，如 "auth/v1.2.3"
+func ParseTagVersion(tag, prefix string) (*TagVersion, error) {
+	rest, ok := strings.CutPrefix(tag, prefix)
+	if !ok {
+		return nil, erero.Errorf("tag %q lacks prefix %q", tag, prefix)
+	}
+	version := &TagVersion{Prefix: prefix}
+	rest, version.V = strings.CutPrefix(rest, "v")
+	matches := semVerRegexp.FindStringSubmatch(rest)
+	if matches == nil {
+		return nil, erero.Errorf("tag %q is not a semantic version", tag)
+	}
+	var err error
+	if version.Major, err = strconv.ParseUint(matches[1], 10, 64); err != nil {
+		return nil, erero.Wro(err)
+	}
+	if version.Minor, err = strconv.ParseUint(matches[2], 10, 64); err != nil {
+		return nil, erero.Wro(err)
+	}
+	if version.Patch, err = strconv.ParseUint(matches[3], 10, 64); err != nil {
+		return nil, erero.Wro(err)
+	}
+	version.Prerelease, version.Build = matches[4], matches[5]
+	return version, nil
+}
+
+// String returns the tag name of the version
+//
+// String 返回该版本的标签名称
+func (v *TagVersion) String() string {
+	var sb strings.Builder
+	sb.WriteString(v.Prefix)
+	if v.V {
+		sb.WriteString("v")
+	}
+	sb.WriteString(strconv.FormatUint(v.Major, 10) + "." + strconv.FormatUint(v.Minor, 10) + "." + strconv.FormatUint(v.Patch, 10))
+	if v.Prerelease != "" {
+		sb.WriteString("-" + v.Prerelease)
+	}
+	if v.Build != "" {
+		sb.WriteString("+" + v.Build)
+	}
+	return sb.String()
+}
+
+// IsPrerelease checks if the version carries prerelease identifiers
+//
+// IsPrerelease 检查版本是否带有预发布标识符
+func (v *TagVersion) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Compare compares SemVer 2.0 precedence, returning -1, 0 or +1
+// Build metadata and prefix do not count, releases rank above their prereleases
+//
+// Compare 比较 SemVer 2.0 优先级，返回 -1、0 或 +1
+// 构建元数据和前缀不参与比较，正式版本高于其预发布版本
+func (v *TagVersion) Compare(other *TagVersion) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return +1
+	case other.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(strings.Split(v.Prerelease, "."), strings.Split(other.Prerelease, "."))
+}
+
+// Bump returns the next version of the bump, keeping prefix and "v" style and dropping build metadata
+//
+// Bump 返回按 bump 递增后的下一个版本，保留前缀和 "v" 风格并丢弃构建元数据
+func (v *TagVersion) Bump(bump VersionBump) (*TagVersion, error) {
+	next := &TagVersion{Prefix: v.Prefix, V: v.V, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch bump {
+	case BumpMajor:
+		// Releasing a major prerelease like 2.0.0-rc.1 keeps the numbers // 发布 2.0.0-rc.1 这类主版本预发布时保留版本号
+		if !v.IsPrerelease() || v.Minor != 0 || v.Patch != 0 {
+			next.Major, next.Minor, next.Patch = v.Major+1, 0, 0
+		}
+	case BumpMinor:
+		if !v.IsPrerelease() || v.Patch != 0 {
+			next.Minor, next.Patch = v.Minor+1, 0
+		}
+	case BumpPatch:
+		if !v.IsPrerelease() {
+			next.Patch = v.Patch + 1
+		}
+	case BumpPrerelease:
+		if !v.IsPrerelease() {
+			next.Patch, next.Prerelease = v.Patch+1, DefaultPrereleaseID+".1"
+			break
+		}
+		ids := strings.Split(v.Prerelease, ".")
+		number, err := strconv.ParseUint(ids[len(ids)-1], 10, 64)
+		if err != nil {
+			ids = append(ids, "1") // Like "alpha" -> "alpha.1" // 如 "alpha" -> "alpha.1"
+		} else {
+			ids[len(ids)-1] = strconv.FormatUint(number+1, 10)
+		}
+		next.Prerelease = strings.Join(ids, ".")
+	default:
+		return nil, erero.Errorf("unknown version bump %q", bump)
+	}
+	return next, nil
+}
+
+// comparePrerelease compares dot separated prerelease identifiers per SemVer 2.0
+// Numeric identifiers compare as numbers and rank below alphanumeric ones, a shorter list ranks lower
+//
+// comparePrerelease 按 SemVer 2.0 比较以点分隔的预发布标识符
+// 数字标识符按数值比较且低于字母数字标识符，较短的列表优先级较低
+func comparePrerelease(a, b []string) int {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		numberA, errA := strconv.ParseUint(a[idx], 10, 64)
+		numberB, errB := strconv.ParseUint(b[idx], 10, 64)
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(numberA, numberB)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = +1
+		default:
+			c = strings.Compare(a[idx], b[idx])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// ListVersionTags gets the tags parsing as semantic versions under the prefix, lowest precedence first
+// Tags under the prefix but not versions are skipped, equal precedence falls back to tag name sequence
+// Use case: find release history of one component in a monorepo
+//
+// ListVersionTags 获取前缀下可解析为语义化版本的标签，优先级最低的在前
+// 前缀下不是版本的标签会被跳过，优先级相同时按标签名称排序
+// 使用场景：查找 monorepo 中某个组件的发布历史
+func (G *Gcm) ListVersionTags(prefix string) ([]*TagVersion, error) {
+	output, err := G.execStdout("git", "for-each-ref", "--format=%(refname:strip=2)", "refs/tags")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var versions []*TagVersion
+	for _, tag := range strings.Split(string(output), "\n") {
+		if tag == "" || !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		if version, err := ParseTagVersion(tag, prefix); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	slices.SortStableFunc(versions, func(a, b *TagVersion) int {
+		if c := a.Compare(b); c != 0 {
+			return c
+		}
+		return strings.Compare(a.String(), b.String())
+	})
+	return versions, nil
+}
+
+// GetLatestVersion gets the highest precedence version tag under the prefix, prereleases included
+// Returns false when no tag under the prefix is a version
+//
+// GetLatestVersion 获取前缀下优先级最高的版本标签，包括预发布版本
+// 前缀下没有版本标签时返回 false
+func (G *Gcm) GetLatestVersion(prefix string) (*TagVersion, bool, error) {
+	versions, err := G.ListVersionTags(prefix)
+	if err != nil {
+		return nil, false, erero.Wro(err)
+	}
+	if len(versions) == 0 {
+		return nil, false, nil
+	}
+	return versions[len(versions)-1], true, nil
+}
+
+// NextVersion computes the version after the latest version tag under the prefix
+// Starts from "<prefix>v0.0.0" when the prefix has no version tags yet
+// Use case: pick the next release tag in CI
+//
+// NextVersion 计算前缀下最新版本标签之后的版本
+// 前缀下还没有版本标签时从 "<prefix>v0.0.0" 开始
+// 使用场景：在 CI 中选择下一个发布标签
+func (G *Gcm) NextVersion(prefix string, bump VersionBump) (*TagVersion, error) {
+	latest, exists, err := G.GetLatestVersion(prefix)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !exists {
+		latest = &TagVersion{Prefix: prefix, V: true}
+	}
+	next, err := latest.Bump(bump)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return next, nil
+}
+
+// CreateNextTag tags HEAD with NextVersion, annotated when message is not blank
+// Git creates the tag ref only when it is free, so concurrent runs cannot move an existing tag
+// Use case: cut releases in CI without computing versions by hand
+//
+// CreateNextTag 使用 NextVersion 标记 HEAD，message 不为空时创建附注标签
+// git 只在标签引用空闲时创建它，因此并发运行无法移动已有标签
+// 使用场景：在 CI 中发布版本而无需手动计算版本
+func (G *Gcm) CreateNextTag(prefix string, bump VersionBump, message string) (*TagVersion, error) {
+	next, err := G.NextVersion(prefix, bump)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var res *Gcm
+	if message == "" {
+		res = G.Tag(next.String())
+	} else {
+		res = G.TagAnnotated(next.String(), message, "")
+	}
+	if err := res.Reason(); err != nil {
+		return nil, erero.Wro(err)
+	}
+	return next, nil
+}
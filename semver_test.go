@@ -0,0 +1,149 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestParseTagVersion tests parsing prefixes, "v" and SemVer parts of tags
+// Verifies non-versions and missing prefixes fail
+//
+// TestParseTagVersion 测试解析标签的前缀、"v" 和 SemVer 部分
+// 验证非版本和缺少前缀时失败
+func TestParseTagVersion(t *testing.T) {
+	version := rese.P1(gitgo.ParseTagVersion("auth/v1.2.3-rc.1+build.5", "auth/"))
+	require.Equal(t, &gitgo.TagVersion{Prefix: "auth/", V: true, Major: 1, Minor: 2, Patch: 3, Prerelease: "rc.1", Build: "build.5"}, version)
+	require.Equal(t, "auth/v1.2.3-rc.1+build.5", version.String())
+	require.True(t, version.IsPrerelease())
+
+	version = rese.P1(gitgo.ParseTagVersion("10.0.1", ""))
+	require.False(t, version.V)
+	require.Equal(t, uint64(10), version.Major)
+	require.Equal(t, "10.0.1", version.String())
+
+	for _, tag := range []string{"v1.2", "v01.2.3", "v1.2.3-01", "v1.2.3-", "release", "auth/v1.2.3"} {
+		_, err := gitgo.ParseTagVersion(tag, "")
+		require.Error(t, err, tag)
+	}
+	_, err := gitgo.ParseTagVersion("v1.2.3", "auth/")
+	require.Error(t, err)
+}
+
+// TestTagVersion_Compare tests SemVer 2.0 precedence with the semver.org example sequence
+// Verifies build metadata does not count
+//
+// TestTagVersion_Compare 使用 semver.org 的示例序列测试 SemVer 2.0 优先级
+// 验证构建元数据不参与比较
+func TestTagVersion_Compare(t *testing.T) {
+	tags := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for idx := 1; idx < len(tags); idx++ {
+		a := rese.P1(gitgo.ParseTagVersion(tags[idx-1], ""))
+		b := rese.P1(gitgo.ParseTagVersion(tags[idx], ""))
+		require.Equal(t, -1, a.Compare(b), tags[idx-1]+" < "+tags[idx])
+		require.Equal(t, +1, b.Compare(a), tags[idx]+" > "+tags[idx-1])
+	}
+	a := rese.P1(gitgo.ParseTagVersion("v1.0.0+build.1", ""))
+	b := rese.P1(gitgo.ParseTagVersion("1.0.0+build.2", ""))
+	require.Equal(t, 0, a.Compare(b))
+}
+
+// TestTagVersion_Bump tests major, minor, patch and prerelease bumps of releases and prereleases
+//
+// TestTagVersion_Bump 测试正式版本和预发布版本的主版本、次版本、修订和预发布递增
+func TestTagVersion_Bump(t *testing.T) {
+	cases := []struct {
+		tag  string
+		bump gitgo.VersionBump
+		want string
+	}{
+		{"v1.2.3", gitgo.BumpMajor, "v2.0.0"},
+		{"v1.2.3", gitgo.BumpMinor, "v1.3.0"},
+		{"v1.2.3+build", gitgo.BumpPatch, "v1.2.4"},
+		{"v1.2.3", gitgo.BumpPrerelease, "v1.2.4-rc.1"},
+		{"v2.0.0-rc.1", gitgo.BumpMajor, "v2.0.0"},
+		{"v1.2.3-rc.1", gitgo.BumpMajor, "v2.0.0"},
+		{"v1.3.0-rc.1", gitgo.BumpMinor, "v1.3.0"},
+		{"v1.2.4-rc.1", gitgo.BumpMinor, "v1.3.0"},
+		{"v1.2.4-rc.1", gitgo.BumpPatch, "v1.2.4"},
+		{"v1.2.4-rc.9", gitgo.BumpPrerelease, "v1.2.4-rc.10"},
+		{"v1.2.4-alpha", gitgo.BumpPrerelease, "v1.2.4-alpha.1"},
+	}
+	for _, c := range cases {
+		version := rese.P1(gitgo.ParseTagVersion(c.tag, ""))
+		require.Equal(t, c.want, rese.P1(version.Bump(c.bump)).String(), c.tag+" "+string(c.bump))
+	}
+	_, err := rese.P1(gitgo.ParseTagVersion("v1.2.3", "")).Bump("huge")
+	require.Error(t, err)
+}
+
+// TestGcm_NextVersion tests listing version tags per prefix and tagging the next version
+// Verifies prereleases sort below releases, unlike version:refname
+//
+// TestGcm_NextVersion 测试按前缀列出版本标签并标记下一个版本
+// 验证预发布版本排在正式版本之下，不同于 version:refname
+func TestGcm_NextVersion(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-semver-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	require.Equal(t, "v0.1.0", rese.P1(gcm.NextVersion("", gitgo.BumpMinor)).String())
+	_, exists, err := gcm.GetLatestVersion("")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	for _, tag := range []string{"v1.0.0", "v1.1.0-rc.1", "v1.1.0", "v1.1.0-rc.2", "release", "auth/v3.0.0", "auth/v3.0.0-beta"} {
+		gcm.Tag(tag).Done()
+	}
+	var names []string
+	for _, version := range rese.V1(gcm.ListVersionTags("")) {
+		names = append(names, version.String())
+	}
+	require.Equal(t, []string{"v1.0.0", "v1.1.0-rc.1", "v1.1.0-rc.2", "v1.1.0"}, names)
+	latest, exists, err := gcm.GetLatestVersion("")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "v1.1.0", latest.String())
+	latest, _, err = gcm.GetLatestVersion("auth/")
+	require.NoError(t, err)
+	require.Equal(t, "auth/v3.0.0", latest.String())
+
+	require.Equal(t, "v1.1.1", rese.P1(gcm.NextVersion("", gitgo.BumpPatch)).String())
+	require.Equal(t, "auth/v4.0.0", rese.P1(gcm.NextVersion("auth/", gitgo.BumpMajor)).String())
+	require.Equal(t, "billing/v0.0.1-rc.1", rese.P1(gcm.NextVersion("billing/", gitgo.BumpPrerelease)).String())
+
+	next := rese.P1(gcm.CreateNextTag("", gitgo.BumpMinor, ""))
+	require.Equal(t, "v1.2.0", next.String())
+	require.True(t, rese.V1(gcm.TagExists("v1.2.0")))
+
+	next = rese.P1(gcm.CreateNextTag("auth/", gitgo.BumpPatch, "auth release"))
+	require.Equal(t, "auth/v3.0.1", next.String())
+	infos := rese.V1(gcm.ListTagInfos())
+	var annotated bool
+	for _, info := range infos {
+		if info.Name == "auth/v3.0.1" {
+			annotated = info.Type == gitgo.TagAnnotated && info.Message == "auth release"
+		}
+	}
+	require.True(t, annotated)
+
+	gcm.Tag("v1.2.1-rc.1").Done()
+	require.Equal(t, "v1.2.1-rc.2", rese.P1(gcm.NextVersion("", gitgo.BumpPrerelease)).String())
+	require.Equal(t, "v1.2.1", rese.P1(gcm.CreateNextTag("", gitgo.BumpPatch, "")).String())
+	_, err = gcm.CreateNextTag("", "huge", "")
+	require.Error(t, err)
+}
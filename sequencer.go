@@ -0,0 +1,208 @@
+package gitgo
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/osexistpath"
+)
+
+// SequencerOperation names the operation driven by the git sequencer
+//
+// SequencerOperation 表示由 git sequencer 驱动的操作
+type SequencerOperation string
+
+const (
+	SequencerCherryPick SequencerOperation = "cherry-pick" // git cherry-pick // git cherry-pick
+	SequencerRevert     SequencerOperation = "revert"      // git revert // git revert
+)
+
+// SequencerState describes the cherry-pick or revert in progress, InProgress is false when none is
+//
+// SequencerState 描述进行中的 cherry-pick 或 revert，没有时 InProgress 为 false
+type SequencerState struct {
+	InProgress bool               // Cherry-pick or revert in progress // cherry-pick 或 revert 进行中
+	Operation  SequencerOperation // Operation in progress // 进行中的操作
+	Current    string             // Commit hash being applied, blank when not stopped at a commit // 正在应用的提交哈希，未停在提交上时为空
+	Remaining  []string           // Commit hashes still to apply after Current // Current 之后仍待应用的提交哈希
+	Conflicts  []string           // Unmerged paths in the worktree // 工作区中的未合并路径
+}
+
+// CherryPick applies the changes of the commits on top of the current branch
+// Stops with a merge conflict error when a commit does not apply, see SequencerState
+// Use case: backport fixes onto release branches
+//
+// CherryPick 将提交的更改应用到当前分支之上
+// 提交无法应用时以合并冲突错误停止，参见 SequencerState
+// 使用场景：将修复向后移植到发布分支
+func (G *Gcm) CherryPick(commits ...string) *Gcm {
+	return G.do("git", append([]string{"cherry-pick"}, commits...)...)
+}
+
+// CherryPickRange applies the commits after from up to and including to, oldest first
+// Same as git cherry-pick from..to, so from itself is not applied
+// Use case: backport a series of fixes in one go
+//
+// CherryPickRange 按从旧到新的顺序应用 from 之后直到 to（含）的提交
+// 等同于 git cherry-pick from..to，因此 from 本身不会被应用
+// 使用场景：一次性向后移植一系列修复
+func (G *Gcm) CherryPickRange(from, to string) *Gcm {
+	return G.do("git", "cherry-pick", from+".."+to)
+}
+
+// CherryPickContinue continues the cherry-pick after conflicts got resolved and staged
+// Keeps the prepared commit messages without opening an editor
+//
+// CherryPickContinue 在冲突被解决并暂存后继续 cherry-pick
+// 保留已准备的提交消息，不打开编辑器
+func (G *Gcm) CherryPickContinue() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "cherry-pick", "--continue")
+}
+
+// CherryPickAbort stops the cherry-pick and restores the branch as it was before
+//
+// CherryPickAbort 停止 cherry-pick 并将分支恢复到之前的状态
+func (G *Gcm) CherryPickAbort() *Gcm {
+	return G.do("git", "cherry-pick", "--abort")
+}
+
+// CherryPickSkip drops the commit the cherry-pick stopped at and continues with the next one
+//
+// CherryPickSkip 丢弃 cherry-pick 停止处的提交并继续下一个
+func (G *Gcm) CherryPickSkip() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "cherry-pick", "--skip")
+}
+
+// Revert creates commits undoing the changes of the commits, using the default messages
+// Fails on merge commits, use RevertMainline for them
+// Use case: back out bad commits without rewriting shared history
+//
+// Revert 创建撤销这些提交更改的提交，使用默认消息
+// 对合并提交会失败，合并提交请使用 RevertMainline
+// 使用场景：在不改写共享历史的情况下撤销错误提交
+func (G *Gcm) Revert(commits ...string) *Gcm {
+	return G.do("git", append([]string{"revert", "--no-edit"}, commits...)...)
+}
+
+// RevertMainline reverts merge commits relative to the parent number mainline, 1 is the merged-into side
+// Use case: back out a feature merge from main
+//
+// RevertMainline 相对于第 mainline 个父提交撤销合并提交，1 为被合并入的一侧
+// 使用场景：从 main 中撤销某个特性的合并
+func (G *Gcm) RevertMainline(mainline int, commits ...string) *Gcm {
+	return G.do("git", append([]string{"revert", "--no-edit", "-m", strconv.Itoa(mainline)}, commits...)...)
+}
+
+// RevertContinue continues the revert after conflicts got resolved and staged
+// Keeps the prepared commit messages without opening an editor
+//
+// RevertContinue 在冲突被解决并暂存后继续 revert
+// 保留已准备的提交消息，不打开编辑器
+func (G *Gcm) RevertContinue() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "revert", "--continue")
+}
+
+// RevertAbort stops the revert and restores the branch as it was before
+//
+// RevertAbort 停止 revert 并将分支恢复到之前的状态
+func (G *Gcm) RevertAbort() *Gcm {
+	return G.do("git", "revert", "--abort")
+}
+
+// RevertSkip drops the commit the revert stopped at and continues with the next one
+//
+// RevertSkip 丢弃 revert 停止处的提交并继续下一个
+func (G *Gcm) RevertSkip() *Gcm {
+	return G.doWith(G.newEditorlessConfig(), "git", "revert", "--skip")
+}
+
+// SequencerState gets the state of the cherry-pick or revert in progress from the git dir
+// Reads CHERRY_PICK_HEAD, REVERT_HEAD and the sequencer todo, conflicts come from the typed status
+// Use case: decide whether backport tooling continues, skips or aborts
+//
+// SequencerState 从 git 目录获取进行中的 cherry-pick 或 revert 的状态
+// 读取 CHERRY_PICK_HEAD、REVERT_HEAD 和 sequencer todo，冲突来自类型化状态
+// 使用场景：决定向后移植工具继续、跳过还是中止
+func (G *Gcm) SequencerState() (*SequencerState, error) {
+	gitDIR, err := G.GetGitDIRAbsPath()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	state, todo, err := readSequencerState(gitDIR)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !state.InProgress {
+		return state, nil
+	}
+	if len(todo) > 0 {
+		// The todo lists abbreviated hashes, starting with the stopped one // todo 列出缩写哈希，以停止处的提交开头
+		output, err := G.execStdout("git", append([]string{"rev-parse"}, todo...)...)
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		for _, hash := range strings.Fields(string(output)) {
+			if hash != state.Current {
+				state.Remaining = append(state.Remaining, hash)
+			}
+		}
+	}
+	status, err := G.GetStatus()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for _, entry := range status.Entries {
+		if entry.Type == StatusUnmerged {
+			state.Conflicts = append(state.Conflicts, entry.Path)
+		}
+	}
+	return state, nil
+}
+
+// readSequencerState reads the operation heads and the todo commits found in gitDIR
+// Single commit picks leave no sequencer dir, only the CHERRY_PICK_HEAD or REVERT_HEAD
+//
+// readSequencerState 读取 gitDIR 中的操作头和 todo 提交
+// 单个提交的 pick 不会留下 sequencer 目录，只有 CHERRY_PICK_HEAD 或 REVERT_HEAD
+func readSequencerState(gitDIR string) (*SequencerState, []string, error) {
+	var state = &SequencerState{}
+	if current := readStateFile(gitDIR, "CHERRY_PICK_HEAD"); current != "" {
+		state.InProgress, state.Operation, state.Current = true, SequencerCherryPick, current
+	} else if current := readStateFile(gitDIR, "REVERT_HEAD"); current != "" {
+		state.InProgress, state.Operation, state.Current = true, SequencerRevert, current
+	}
+
+	todoPath := filepath.Join(gitDIR, "sequencer", "todo")
+	todoExists, err := osexistpath.IsFile(todoPath)
+	if err != nil {
+		return nil, nil, erero.Wro(err)
+	}
+	if !todoExists {
+		return state, nil, nil
+	}
+	data, err := os.ReadFile(todoPath)
+	if err != nil {
+		return nil, nil, erero.Wro(err)
+	}
+	var todo []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if !state.InProgress {
+			// Stopped after a resolved commit, the todo tells the operation // 在已解决的提交后停止，由 todo 确定操作
+			state.InProgress = true
+			if fields[0] == "revert" || fields[0] == "r" {
+				state.Operation = SequencerRevert
+			} else {
+				state.Operation = SequencerCherryPick
+			}
+		}
+		todo = append(todo, fields[1])
+	}
+	return state, todo, nil
+}
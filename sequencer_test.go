@@ -0,0 +1,71 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_CherryPick tests picking a range that stops on conflicts and continuing
+// Verifies the sequencer state reports the current commit and the remaining ones
+//
+// TestGcm_CherryPick 测试 pick 一个在冲突时停止的范围并继续
+// 验证 sequencer 状态报告当前提交和剩余提交
+func TestGcm_CherryPick(t *testing.T) {
+	gcm, tempDIR := newRebaseTestRepo(t)
+	gcm.Checkout("main").Done()
+	require.False(t, rese.P1(gcm.SequencerState()).InProgress)
+
+	err := gcm.CherryPickRange("main~1", "feature").Reason()
+	require.True(t, gitgo.IsMergeConflict(err))
+
+	state := rese.P1(gcm.SequencerState())
+	require.True(t, state.InProgress)
+	require.Equal(t, gitgo.SequencerCherryPick, state.Operation)
+	require.Equal(t, rese.C1(gcm.GetCommitHash("feature~1")), state.Current)
+	require.Equal(t, []string{rese.C1(gcm.GetCommitHash("feature"))}, state.Remaining)
+	require.Equal(t, []string{"a.txt"}, state.Conflicts)
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("resolved\n"), 0644))
+	gcm.Add().CherryPickContinue().Done()
+	require.False(t, rese.P1(gcm.SequencerState()).InProgress)
+	require.Equal(t, "feature add", rese.C1(gcm.GetCommitMessage("HEAD")))
+	require.Equal(t, "feature edit", rese.C1(gcm.GetCommitMessage("HEAD~1")))
+
+	require.Error(t, gcm.CherryPick("feature~1").Reason())
+	gcm.CherryPickSkip().Done()
+	require.Error(t, gcm.CherryPick("feature~1").Reason())
+	gcm.CherryPickAbort().Done()
+	require.False(t, rese.P1(gcm.SequencerState()).InProgress)
+	require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+}
+
+// TestGcm_Revert tests reverting plain commits and merge commits with mainline
+//
+// TestGcm_Revert 测试撤销普通提交以及使用 mainline 撤销合并提交
+func TestGcm_Revert(t *testing.T) {
+	gcm, tempDIR := newLogTestRepo(t)
+
+	gcm.Revert("HEAD~1").Done()
+	require.NoFileExists(t, filepath.Join(tempDIR, "c.txt"))
+	require.Contains(t, rese.C1(gcm.GetCommitMessage("HEAD")), "Revert \"main work\"")
+
+	require.Error(t, gcm.Revert("HEAD~1").Reason())
+	gcm.RevertMainline(1, "HEAD~1").Done()
+	require.NoFileExists(t, filepath.Join(tempDIR, "b.txt"))
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("changed"), 0644))
+	gcm.Add().Commit("change a").Done()
+	err := gcm.Revert("HEAD~4", "HEAD").Reason()
+	require.Error(t, err)
+	state := rese.P1(gcm.SequencerState())
+	require.True(t, state.InProgress)
+	require.Equal(t, gitgo.SequencerRevert, state.Operation)
+	gcm.RevertAbort().Done()
+	require.False(t, rese.P1(gcm.SequencerState()).InProgress)
+}
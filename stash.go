@@ -0,0 +1,239 @@
+package gitgo
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// stashFormat is the --format of ListStashes, fields split by NUL and entries ended by -z NUL
+//
+// stashFormat 是 ListStashes 使用的 --format 格式，字段以 NUL 分隔，条目以 -z 的 NUL 结尾
+const stashFormat = "%gd%x00%H%x00%gs%x00%cI"
+
+// stashFieldCount is the count of NUL separated fields in each entry of stashFormat
+//
+// stashFieldCount 是 stashFormat 中每个条目以 NUL 分隔的字段数量
+const stashFieldCount = 4
+
+// withStashedMessage is the message of stashes created by WithStashed
+//
+// withStashedMessage 是 WithStashed 创建的 stash 的消息
+const withStashedMessage = "gitgo: WithStashed"
+
+// StashEntry is one typed entry of git stash list
+//
+// StashEntry 是 git stash list 的一个类型化条目
+type StashEntry struct {
+	Index   int       // Position in the stash stack, 0 is the newest // 在 stash 栈中的位置，0 为最新
+	Ref     string    // Reflog ref like "stash@{0}" // 引用日志引用，如 "stash@{0}"
+	Hash    string    // Stash commit hash // stash 提交哈希
+	Branch  string    // Branch the stash was made on, "(no branch)" when detached // 创建 stash 时的分支，游离时为 "(no branch)"
+	Message string    // Stash message, "<short hash> <subject>" when made without message // stash 消息，未指定消息时为 "<短哈希> <主题>"
+	Time    time.Time // Time the stash was made // 创建 stash 的时间
+}
+
+// Stash parks tracked changes of the worktree and index on the stash stack
+// Leaves the worktree clean at HEAD, untracked files stay in place
+// Use case: park local edits before Pull and Checkout
+//
+// Stash 将工作区和暂存区的跟踪文件更改存入 stash 栈
+// 使工作区回到干净的 HEAD 状态，未跟踪文件保持不动
+// 使用场景：在 Pull 和 Checkout 前暂存本地编辑
+func (G *Gcm) Stash(message string) *Gcm {
+	return G.do("git", "stash", "push", "-m", message)
+}
+
+// StashIncludeUntracked parks tracked changes and untracked files on the stash stack
+// Ignored files stay in place
+// Use case: park generated sources and new files along with edits
+//
+// StashIncludeUntracked 将跟踪文件更改和未跟踪文件存入 stash 栈
+// 被忽略的文件保持不动
+// 使用场景：将生成的源码和新文件与编辑一起暂存
+func (G *Gcm) StashIncludeUntracked(message string) *Gcm {
+	return G.do("git", "stash", "push", "--include-untracked", "-m", message)
+}
+
+// StashPop restores the newest stash and drops it from the stack
+// Keeps the stash when restoring conflicts
+// Use case: bring parked edits back after Pull and Checkout
+//
+// StashPop 恢复最新的 stash 并将其从栈中删除
+// 恢复产生冲突时保留该 stash
+// 使用场景：在 Pull 和 Checkout 后恢复暂存的编辑
+func (G *Gcm) StashPop() *Gcm {
+	return G.do("git", "stash", "pop")
+}
+
+// StashApply restores the stash at ref like "stash@{1}" and keeps it on the stack
+// Use case: apply the same parked edits to several branches
+//
+// StashApply 恢复 ref（如 "stash@{1}"）处的 stash 并保留在栈中
+// 使用场景：将同一组暂存编辑应用到多个分支
+func (G *Gcm) StashApply(ref string) *Gcm {
+	return G.do("git", "stash", "apply", ref)
+}
+
+// StashDrop removes the stash at ref like "stash@{1}" from the stack
+// Use case: discard parked edits no longer needed
+//
+// StashDrop 从栈中删除 ref（如 "stash@{1}"）处的 stash
+// 使用场景：丢弃不再需要的暂存编辑
+func (G *Gcm) StashDrop(ref string) *Gcm {
+	return G.do("git", "stash", "drop", ref)
+}
+
+// WithStashed stashes local changes (untracked included), runs the body and restores them
+// Restores even when the body fails, both failures get joined when restoring fails too
+// Restores before re-raising when the body panics, like with Done and Must
+// Pops the exact stash it created, so stashes the body pushes stay in the stack
+// Nothing gets stashed or restored when the worktree is clean
+// Use case: run Pull and Checkout sequences without losing local edits
+//
+// WithStashed 暂存本地更改（包括未跟踪文件），运行 body 后恢复
+// 即使 body 失败也会恢复，恢复也失败时两个错误会合并
+// body 发生 panic 时（如 Done 和 Must），先恢复再重新抛出
+// 只弹出自己创建的那个 stash，因此 body 推入的 stash 会保留在栈中
+// 工作区干净时不暂存也不恢复
+// 使用场景：运行 Pull 和 Checkout 序列而不丢失本地编辑
+func (G *Gcm) WithStashed(run func(*Gcm) *Gcm) *Gcm {
+	if G.errorOnce != nil {
+		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
+	}
+	previous, err := G.getStashHash()
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	stashed := G.StashIncludeUntracked(withStashedMessage)
+	if stashed.errorOnce != nil {
+		return stashed
+	}
+	current, err := G.getStashHash()
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	if current == previous {
+		return run(stashed) // Clean worktree, nothing to restore // 工作区干净，无需恢复
+	}
+	defer func() {
+		if reason := recover(); reason != nil {
+			G.restoreStash(current) // Failures get logged in debug mode, the panic goes on // 失败会在调试模式下记录，panic 继续抛出
+			panic(reason)
+		}
+	}()
+	result := run(stashed)
+	restored := G.restoreStash(current)
+	switch {
+	case restored.errorOnce != nil && result.errorOnce != nil:
+		return newWaGcm(G.execConfig, G.options, result.output, erero.Join(result.errorOnce, restored.errorOnce), G.debugMode)
+	case restored.errorOnce != nil:
+		return newWaGcm(G.execConfig, G.options, restored.output, erero.Wrapf(restored.errorOnce, "restore stash %s", current), G.debugMode)
+	default:
+		return result
+	}
+}
+
+// restoreStash pops the stash entry with the hash, wherever it sits in the stack
+//
+// restoreStash 弹出具有该哈希的 stash 条目，无论它位于栈中何处
+func (G *Gcm) restoreStash(hash string) *Gcm {
+	base := newOkGcm(G.execConfig, G.options, []byte{}, G.debugMode)
+	entries, err := base.ListStashes()
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	for _, entry := range entries {
+		if entry.Hash == hash {
+			return base.do("git", "stash", "pop", entry.Ref)
+		}
+	}
+	return newWaGcm(G.execConfig, G.options, []byte{}, erero.Errorf("stash %s is gone from the stack", hash), G.debugMode)
+}
+
+// getStashHash gets the hash of refs/stash, blank when the stash stack is empty
+//
+// getStashHash 获取 refs/stash 的哈希，stash 栈为空时为空字符串
+func (G *Gcm) getStashHash() (string, error) {
+	output, exc, err := G.execTake(G.execConfig.NewConfig().WithExpectExit(1, "NO-STASH"), "git", "rev-parse", "-q", "--verify", "refs/stash")
+	if err != nil {
+		return "", erero.Wro(err)
+	}
+	if exc == 1 {
+		return "", nil
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListStashes gets typed entries of the stash stack, newest first
+// Returns index, ref, hash, branch, message and time of each entry
+// Use case: find parked edits to apply or drop
+//
+// ListStashes 获取 stash 栈的类型化条目，最新的在前
+// 返回每个条目的索引、引用、哈希、分支、消息和时间
+// 使用场景：查找要应用或删除的暂存编辑
+func (G *Gcm) ListStashes() ([]StashEntry, error) {
+	output, err := G.execStdout("git", "stash", "list", "-z", "--format="+stashFormat)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseStashList(output)
+}
+
+// parseStashList parses the NUL separated fields of stashFormat into entries
+//
+// parseStashList 将 stashFormat 以 NUL 分隔的字段解析为条目
+func parseStashList(output []byte) ([]StashEntry, error) {
+	fields := bytes.Split(output, []byte{0})
+	// Output ends with NUL, leaving one blank tail item // 输出以 NUL 结尾，留下一个空尾项
+	if len(fields) > 0 && len(fields[len(fields)-1]) == 0 {
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields)%stashFieldCount != 0 {
+		return nil, erero.Errorf("wrong stash list field count %d", len(fields))
+	}
+	entries := make([]StashEntry, 0, len(fields)/stashFieldCount)
+	for idx := 0; idx < len(fields); idx += stashFieldCount {
+		ref := string(fields[idx])
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(ref, "stash@{"), "}"))
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		stashTime, err := time.Parse(time.RFC3339, string(fields[idx+3]))
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		branch, message := parseStashSubject(string(fields[idx+2]))
+		entries = append(entries, StashEntry{
+			Index:   index,
+			Ref:     ref,
+			Hash:    string(fields[idx+1]),
+			Branch:  branch,
+			Message: message,
+			Time:    stashTime,
+		})
+	}
+	return entries, nil
+}
+
+// parseStashSubject splits "On <branch>: <message>" and "WIP on <branch>: <message>" subjects
+// Branch names cannot contain ':', so the first ": " ends the branch
+//
+// parseStashSubject 拆分 "On <branch>: <message>" 和 "WIP on <branch>: <message>" 主题
+// 分支名不能包含 ':'，因此第一个 ": " 即分支结尾
+func parseStashSubject(subject string) (string, string) {
+	rest, ok := strings.CutPrefix(subject, "WIP on ")
+	if !ok {
+		if rest, ok = strings.CutPrefix(subject, "On "); !ok {
+			return "", subject
+		}
+	}
+	branch, message, ok := strings.Cut(rest, ": ")
+	if !ok {
+		return "", subject
+	}
+	return branch, message
+}
@@ -0,0 +1,138 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Stash tests stashing, listing, applying, dropping and popping edits
+// Verifies typed entries carry index, ref, branch and message
+//
+// TestGcm_Stash 测试暂存、列出、应用、删除和弹出编辑
+// 验证类型化条目包含索引、引用、分支和消息
+func TestGcm_Stash(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-stash-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	require.Empty(t, rese.V1(gcm.ListStashes()))
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("edit"), 0644))
+	gcm.Stash("first: edit").Done()
+	require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "new.txt"), []byte("new"), 0644))
+	gcm.StashIncludeUntracked("second").Done()
+	require.NoFileExists(t, filepath.Join(tempDIR, "new.txt"))
+
+	stashes := rese.V1(gcm.ListStashes())
+	require.Len(t, stashes, 2)
+	require.Equal(t, 0, stashes[0].Index)
+	require.Equal(t, "stash@{0}", stashes[0].Ref)
+	require.Equal(t, "second", stashes[0].Message)
+	require.Equal(t, 1, stashes[1].Index)
+	require.Equal(t, "main", stashes[1].Branch)
+	require.Equal(t, "first: edit", stashes[1].Message)
+	require.Len(t, stashes[1].Hash, 40)
+	require.False(t, stashes[1].Time.IsZero())
+
+	gcm.StashApply("stash@{1}").Done()
+	require.Equal(t, "edit", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "a.txt")))))
+	gcm.ResetHard().StashDrop("stash@{1}").StashPop().Done()
+	require.FileExists(t, filepath.Join(tempDIR, "new.txt"))
+	require.Empty(t, rese.V1(gcm.ListStashes()))
+}
+
+// TestGcm_WithStashed tests restoring edits after the body, even when the body fails
+// Verifies a clean worktree creates and restores no stash
+//
+// TestGcm_WithStashed 测试在 body 之后恢复编辑，即使 body 失败也会恢复
+// 验证干净的工作区不会创建和恢复 stash
+func TestGcm_WithStashed(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-stash-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	gcm.CheckoutNewBranch("feature").Checkout("main").Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("edit"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "new.txt"), []byte("new"), 0644))
+	gcm.WithStashed(func(gcm *gitgo.Gcm) *gitgo.Gcm {
+		require.True(t, rese.P1(gcm.GetStatus()).IsClean())
+		return gcm.Checkout("feature").Checkout("main")
+	}).Done()
+	require.Equal(t, "edit", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "a.txt")))))
+	require.FileExists(t, filepath.Join(tempDIR, "new.txt"))
+	require.Empty(t, rese.V1(gcm.ListStashes()))
+
+	err := gcm.WithStashed(func(gcm *gitgo.Gcm) *gitgo.Gcm {
+		return gcm.Checkout("missing-branch")
+	}).Reason()
+	require.Error(t, err)
+	require.Equal(t, "edit", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "a.txt")))))
+	require.Empty(t, rese.V1(gcm.ListStashes()))
+
+	gcm.Add().Commit("edit").Done()
+	gcm.WithStashed(func(gcm *gitgo.Gcm) *gitgo.Gcm {
+		return gcm.Status()
+	}).Done()
+	require.Empty(t, rese.V1(gcm.ListStashes()))
+}
+
+// TestGcm_WithStashed_Panic tests restoring edits when the body panics through Done
+// Verifies the panic goes on and only the stash WithStashed created gets popped
+//
+// TestGcm_WithStashed_Panic 测试 body 通过 Done 发生 panic 时恢复编辑
+// 验证 panic 继续抛出且只弹出 WithStashed 创建的 stash
+func TestGcm_WithStashed_Panic(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-stash-panic-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("older"), 0644))
+	gcm.Stash("older edit").Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("edit"), 0644))
+	require.Panics(t, func() {
+		gcm.WithStashed(func(gcm *gitgo.Gcm) *gitgo.Gcm {
+			return gcm.Checkout("nope").Done()
+		})
+	})
+	require.Equal(t, "edit", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "a.txt")))))
+	stashes := rese.V1(gcm.ListStashes())
+	require.Len(t, stashes, 1)
+	require.NotContains(t, stashes[0].Message, "WithStashed")
+
+	// The body pushes its own stash on top, WithStashed must leave it there
+	// body 在栈顶推入自己的 stash，WithStashed 必须保留它
+	gcm.WithStashed(func(gcm *gitgo.Gcm) *gitgo.Gcm {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, "body.txt"), []byte("body"), 0644))
+		return gcm.StashIncludeUntracked("body stash")
+	}).Done()
+	require.Equal(t, "edit", string(rese.V1(os.ReadFile(filepath.Join(tempDIR, "a.txt")))))
+	require.NoFileExists(t, filepath.Join(tempDIR, "body.txt"))
+	stashes = rese.V1(gcm.ListStashes())
+	require.Len(t, stashes, 2)
+	require.Contains(t, stashes[0].Message, "body stash")
+}
@@ -0,0 +1,276 @@
+package gitgo
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// StatusEntryType tells which porcelain v2 record kind an entry came from
+//
+// StatusEntryType 表示条目来自哪种 porcelain v2 记录类型
+type StatusEntryType string
+
+const (
+	StatusOrdinary  StatusEntryType = "ordinary"  // Changed tracked entry, record "1" // 已修改的跟踪条目，记录 "1"
+	StatusRenamed   StatusEntryType = "renamed"   // Renamed or copied entry, record "2" // 重命名或复制的条目，记录 "2"
+	StatusUnmerged  StatusEntryType = "unmerged"  // Unmerged entry, record "u" // 未合并条目，记录 "u"
+	StatusUntracked StatusEntryType = "untracked" // Untracked entry, record "?" // 未跟踪条目，记录 "?"
+	StatusIgnored   StatusEntryType = "ignored"   // Ignored entry, record "!" // 被忽略条目，记录 "!"
+)
+
+// RepoStatus is the typed result of git status --porcelain=v2 --branch
+// Branch holds the header info, Entries keep git's output sequence
+//
+// RepoStatus 是 git status --porcelain=v2 --branch 的类型化结果
+// Branch 保存头部信息，Entries 保持 git 的输出顺序
+type RepoStatus struct {
+	Branch  BranchStatus  // Branch header info // 分支头部信息
+	Entries []StatusEntry // Changed, untracked and ignored entries // 已修改、未跟踪和被忽略的条目
+}
+
+// BranchStatus holds the "# branch.*" headers of porcelain v2 output
+// Ahead and Behind stay 0 when no upstream is configured or the upstream is gone
+//
+// BranchStatus 保存 porcelain v2 输出的 "# branch.*" 头部
+// 未配置上游或上游已消失时 Ahead 和 Behind 保持为 0
+type BranchStatus struct {
+	Oid      string // HEAD commit, "(initial)" before the first commit // HEAD 提交，首次提交前为 "(initial)"
+	Head     string // Branch name, "(detached)" when detached // 分支名称，分离状态时为 "(detached)"
+	Upstream string // Upstream branch like "origin/main", blank when unset // 上游分支如 "origin/main"，未设置时为空
+	Ahead    int    // Commits ahead of upstream // 领先上游的提交数
+	Behind   int    // Commits behind upstream // 落后上游的提交数
+}
+
+// IsDetached checks if HEAD is detached
+//
+// IsDetached 检查 HEAD 是否处于分离状态
+func (b *BranchStatus) IsDetached() bool {
+	return b.Head == "(detached)"
+}
+
+// IsInitial checks if the branch has no commits yet
+//
+// IsInitial 检查分支是否还没有提交
+func (b *BranchStatus) IsInitial() bool {
+	return b.Oid == "(initial)"
+}
+
+// StatusEntry is one path record of porcelain v2 output
+// Fields not present in the record kind stay blank
+//
+// StatusEntry 是 porcelain v2 输出中的一条路径记录
+// 该记录类型中不存在的字段保持为空
+type StatusEntry struct {
+	Type         StatusEntryType // Record kind // 记录类型
+	XY           string          // Two-char index and worktree codes, like ".M" // 两字符的索引和工作树状态码，如 ".M"
+	Submodule    SubmoduleState  // Submodule state // 子模块状态
+	ModeHEAD     string          // File mode in HEAD // HEAD 中的文件模式
+	ModeIndex    string          // File mode in index // 索引中的文件模式
+	ModeWorktree string          // File mode in worktree // 工作树中的文件模式
+	HashHEAD     string          // Object name in HEAD // HEAD 中的对象名
+	HashIndex    string          // Object name in index // 索引中的对象名
+	StageModes   [3]string       // Unmerged stage 1-3 modes // 未合并条目阶段 1-3 的模式
+	StageHashes  [3]string       // Unmerged stage 1-3 object names // 未合并条目阶段 1-3 的对象名
+	Score        string          // Rename or copy score like "R100" // 重命名或复制分数，如 "R100"
+	Path         string          // Path, raw bytes without quoting // 路径，无引号的原始字节
+	OrigPath     string          // Rename or copy source path // 重命名或复制的源路径
+}
+
+// IndexCode returns the staged status code (X), '.' when unchanged
+//
+// IndexCode 返回暂存状态码（X），未更改时为 '.'
+func (e *StatusEntry) IndexCode() byte {
+	if len(e.XY) < 2 {
+		return '.'
+	}
+	return e.XY[0]
+}
+
+// WorktreeCode returns the unstaged status code (Y), '.' when unchanged
+//
+// WorktreeCode 返回未暂存状态码（Y），未更改时为 '.'
+func (e *StatusEntry) WorktreeCode() byte {
+	if len(e.XY) < 2 {
+		return '.'
+	}
+	return e.XY[1]
+}
+
+// SubmoduleState is the parsed <sub> field of porcelain v2 records
+//
+// SubmoduleState 是 porcelain v2 记录中解析后的 <sub> 字段
+type SubmoduleState struct {
+	IsSubmodule     bool // Entry is a submodule // 条目是子模块
+	CommitChanged   bool // Recorded commit changed // 记录的提交已更改
+	TrackedChanges  bool // Submodule has tracked changes // 子模块有跟踪文件更改
+	UntrackedExists bool // Submodule has untracked files // 子模块有未跟踪文件
+}
+
+// IsClean checks if no staged, unstaged, unmerged or untracked entries exist
+// Ignored entries do not count as changes
+//
+// IsClean 检查是否不存在已暂存、未暂存、未合并或未跟踪的条目
+// 被忽略的条目不算作更改
+func (s *RepoStatus) IsClean() bool {
+	for _, entry := range s.Entries {
+		if entry.Type != StatusIgnored {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStatus gets the typed repo status from git status --porcelain=v2 -z --branch
+// Returns branch head, upstream, ahead/behind and changed and untracked entries
+// Use case: drive tooling from typed status instead of parsing human text
+//
+// GetStatus 从 git status --porcelain=v2 -z --branch 获取类型化的仓库状态
+// 返回分支头、上游、领先/落后数以及已修改和未跟踪的条目
+// 使用场景：基于类型化状态驱动工具而非解析人类可读文本
+func (G *Gcm) GetStatus() (*RepoStatus, error) {
+	output, err := G.execStdout("git", "status", "--porcelain=v2", "-z", "--branch")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseStatusPorcelainV2(output)
+}
+
+// GetStatusIgnored gets the typed repo status including ignored entries
+// Same as GetStatus with --ignored, ignored directories are reported as one entry
+// Use case: find ignored paths when cleaning workspace
+//
+// GetStatusIgnored 获取包含被忽略条目的类型化仓库状态
+// 等同于带 --ignored 的 GetStatus，被忽略的目录作为一个条目报告
+// 使用场景：在清理工作空间时查找被忽略的路径
+func (G *Gcm) GetStatusIgnored() (*RepoStatus, error) {
+	output, err := G.execStdout("git", "status", "--porcelain=v2", "-z", "--branch", "--ignored")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseStatusPorcelainV2(output)
+}
+
+// parseStatusPorcelainV2 parses NUL-terminated porcelain v2 records
+// Paths are taken as raw bytes, so spaces, quotes and non-UTF-8 names stay intact
+//
+// parseStatusPorcelainV2 解析以 NUL 结尾的 porcelain v2 记录
+// 路径按原始字节获取，因此空格、引号和非 UTF-8 名称保持不变
+func parseStatusPorcelainV2(output []byte) (*RepoStatus, error) {
+	status := &RepoStatus{}
+	records := bytes.Split(output, []byte{0})
+	for idx := 0; idx < len(records); idx++ {
+		record := string(records[idx])
+		if record == "" {
+			continue
+		}
+		switch record[0] {
+		case '#':
+			if err := parseStatusHeader(&status.Branch, record); err != nil {
+				return nil, err
+			}
+		case '1':
+			fields := strings.SplitN(record, " ", 9)
+			if len(fields) != 9 {
+				return nil, erero.Errorf("wrong ordinary status record: %q", record)
+			}
+			status.Entries = append(status.Entries, StatusEntry{
+				Type:         StatusOrdinary,
+				XY:           fields[1],
+				Submodule:    parseSubmoduleState(fields[2]),
+				ModeHEAD:     fields[3],
+				ModeIndex:    fields[4],
+				ModeWorktree: fields[5],
+				HashHEAD:     fields[6],
+				HashIndex:    fields[7],
+				Path:         fields[8],
+			})
+		case '2':
+			fields := strings.SplitN(record, " ", 10)
+			if len(fields) != 10 || idx+1 >= len(records) {
+				return nil, erero.Errorf("wrong renamed status record: %q", record)
+			}
+			idx++ // The source path comes as the next NUL-terminated field // 源路径作为下一个 NUL 结尾字段给出
+			status.Entries = append(status.Entries, StatusEntry{
+				Type:         StatusRenamed,
+				XY:           fields[1],
+				Submodule:    parseSubmoduleState(fields[2]),
+				ModeHEAD:     fields[3],
+				ModeIndex:    fields[4],
+				ModeWorktree: fields[5],
+				HashHEAD:     fields[6],
+				HashIndex:    fields[7],
+				Score:        fields[8],
+				Path:         fields[9],
+				OrigPath:     string(records[idx]),
+			})
+		case 'u':
+			fields := strings.SplitN(record, " ", 11)
+			if len(fields) != 11 {
+				return nil, erero.Errorf("wrong unmerged status record: %q", record)
+			}
+			status.Entries = append(status.Entries, StatusEntry{
+				Type:         StatusUnmerged,
+				XY:           fields[1],
+				Submodule:    parseSubmoduleState(fields[2]),
+				StageModes:   [3]string{fields[3], fields[4], fields[5]},
+				ModeWorktree: fields[6],
+				StageHashes:  [3]string{fields[7], fields[8], fields[9]},
+				Path:         fields[10],
+			})
+		case '?':
+			status.Entries = append(status.Entries, StatusEntry{Type: StatusUntracked, Path: strings.TrimPrefix(record, "? ")})
+		case '!':
+			status.Entries = append(status.Entries, StatusEntry{Type: StatusIgnored, Path: strings.TrimPrefix(record, "! ")})
+		default:
+			return nil, erero.Errorf("unknown status record: %q", record)
+		}
+	}
+	return status, nil
+}
+
+// parseStatusHeader fills branch info from one "# branch.*" header
+// Unknown headers (like "# stash") are skipped
+//
+// parseStatusHeader 从一条 "# branch.*" 头部填充分支信息
+// 未知头部（如 "# stash"）被跳过
+func parseStatusHeader(branch *BranchStatus, record string) error {
+	key, value, _ := strings.Cut(strings.TrimPrefix(record, "# "), " ")
+	switch key {
+	case "branch.oid":
+		branch.Oid = value
+	case "branch.head":
+		branch.Head = value
+	case "branch.upstream":
+		branch.Upstream = value
+	case "branch.ab":
+		aheadText, behindText, _ := strings.Cut(value, " ")
+		ahead, err := strconv.Atoi(strings.TrimPrefix(aheadText, "+"))
+		if err != nil {
+			return erero.Wro(err)
+		}
+		behind, err := strconv.Atoi(strings.TrimPrefix(behindText, "-"))
+		if err != nil {
+			return erero.Wro(err)
+		}
+		branch.Ahead, branch.Behind = ahead, behind
+	}
+	return nil
+}
+
+// parseSubmoduleState parses the 4-char <sub> field, "N..." means not a submodule
+//
+// parseSubmoduleState 解析 4 字符的 <sub> 字段，"N..." 表示不是子模块
+func parseSubmoduleState(sub string) SubmoduleState {
+	if len(sub) != 4 || sub[0] != 'S' {
+		return SubmoduleState{}
+	}
+	return SubmoduleState{
+		IsSubmodule:     true,
+		CommitChanged:   sub[1] == 'C',
+		TrackedChanges:  sub[2] == 'M',
+		UntrackedExists: sub[3] == 'U',
+	}
+}
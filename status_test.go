@@ -0,0 +1,110 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_GetStatus tests typed status on ordinary, renamed and untracked entries
+// Verifies raw paths with spaces, quotes and non-UTF-8 bytes stay intact
+//
+// TestGcm_GetStatus 测试普通、重命名和未跟踪条目的类型化状态
+// 验证含空格、引号和非 UTF-8 字节的原始路径保持不变
+func TestGcm_GetStatus(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-status-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	status := rese.P1(gcm.GetStatus())
+	require.True(t, status.Branch.IsInitial())
+	require.Equal(t, "main", status.Branch.Head)
+	require.True(t, status.IsClean())
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("v1"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "old name.txt"), []byte("rename me, content long enough"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, ".gitignore"), []byte("*.log\n"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	must.Done(os.Rename(filepath.Join(tempDIR, "old name.txt"), filepath.Join(tempDIR, "new name.txt")))
+	gcm.Add().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("v2"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "say \"hi\".txt"), []byte("x"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "\xff\xfe.txt"), []byte("x"), 0644))
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "build.log"), []byte("x"), 0644))
+
+	status = rese.P1(gcm.GetStatus())
+	require.False(t, status.Branch.IsInitial())
+	require.False(t, status.Branch.IsDetached())
+	require.Len(t, status.Branch.Oid, 40)
+	require.False(t, status.IsClean())
+
+	entries := map[string]gitgo.StatusEntry{}
+	for _, entry := range status.Entries {
+		entries[entry.Path] = entry
+	}
+	require.Len(t, entries, 4)
+
+	modified := entries["a.txt"]
+	require.Equal(t, gitgo.StatusOrdinary, modified.Type)
+	require.Equal(t, ".M", modified.XY)
+	require.Equal(t, byte('M'), modified.WorktreeCode())
+	require.Equal(t, "100644", modified.ModeHEAD)
+	require.False(t, modified.Submodule.IsSubmodule)
+
+	renamed := entries["new name.txt"]
+	require.Equal(t, gitgo.StatusRenamed, renamed.Type)
+	require.Equal(t, byte('R'), renamed.IndexCode())
+	require.Equal(t, "old name.txt", renamed.OrigPath)
+	require.Equal(t, "R100", renamed.Score)
+
+	require.Equal(t, gitgo.StatusUntracked, entries["say \"hi\".txt"].Type)
+	require.Equal(t, gitgo.StatusUntracked, entries["\xff\xfe.txt"].Type)
+
+	ignoredStatus := rese.P1(gcm.GetStatusIgnored())
+	var ignored []string
+	for _, entry := range ignoredStatus.Entries {
+		if entry.Type == gitgo.StatusIgnored {
+			ignored = append(ignored, entry.Path)
+		}
+	}
+	require.Equal(t, []string{"build.log"}, ignored)
+}
+
+// TestGcm_GetStatus_Unmerged tests typed status on a conflicting merge
+// Verifies unmerged entry XY code and stage object names
+//
+// TestGcm_GetStatus_Unmerged 测试冲突合并时的类型化状态
+// 验证未合并条目的 XY 状态码和各阶段对象名
+func TestGcm_GetStatus_Unmerged(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-status-unmerged-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("base"), 0644))
+	gcm.Add().Commit("base").CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("feature"), 0644))
+	gcm.Add().Commit("feature").Checkout("main").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "file.txt"), []byte("main"), 0644))
+	gcm.Add().Commit("main").Done()
+	require.Error(t, gcm.Merge("feature").Reason())
+
+	status := rese.P1(gcm.GetStatus())
+	require.Len(t, status.Entries, 1)
+	entry := status.Entries[0]
+	require.Equal(t, gitgo.StatusUnmerged, entry.Type)
+	require.Equal(t, "UU", entry.XY)
+	require.Equal(t, "file.txt", entry.Path)
+	for _, hash := range entry.StageHashes {
+		require.Len(t, hash, 40)
+	}
+}
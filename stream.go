@@ -0,0 +1,166 @@
+package gitgo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/zaplog"
+)
+
+// LogSeq streams typed commits selected with opts, reading git stdout incrementally
+// Breaking out of the loop kills the git process, the output never gets buffered in full
+// The After cursor gets handled in stream by skipping commits up to it
+// Use case: walk monorepo histories with hundreds of thousands of commits
+//
+// LogSeq 流式返回由 opts 选择的类型化提交，增量读取 git 标准输出
+// 跳出循环会终止 git 进程，输出永远不会被完整缓存
+// After 游标在流中通过跳过其之前的提交来处理
+// 使用场景：遍历包含数十万提交的大仓库历史
+func (G *Gcm) LogSeq(opts LogOptions) iter.Seq2[Commit, error] {
+	return func(yield func(Commit, error) bool) {
+		cursor := opts.After
+		pageOpts := opts
+		pageOpts.After = ""
+		pageOpts.Limit = 0
+		pageOpts.Skip = 0
+		args, err := pageOpts.makeArgs(logCommand...)
+		if err != nil {
+			yield(Commit{}, erero.Wro(err))
+			return
+		}
+		skip, count := opts.Skip, 0
+		fields := make([][]byte, 0, logFieldCount)
+		for record, err := range G.stream(0, "git", args...) {
+			if err != nil {
+				yield(Commit{}, erero.Wro(err))
+				return
+			}
+			if fields = append(fields, record); len(fields) < logFieldCount {
+				continue
+			}
+			commit, err := parseLogFields(fields)
+			if err != nil {
+				yield(Commit{}, erero.Wro(err))
+				return
+			}
+			fields = fields[:0]
+			if cursor != "" {
+				if commit.Hash == cursor {
+					cursor = "" // Cursor found, commits after it get yielded // 找到游标，其后的提交将被返回
+				}
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			if !yield(commit, nil) {
+				return
+			}
+			if count++; opts.Limit > 0 && count >= opts.Limit {
+				return
+			}
+		}
+		if cursor != "" {
+			yield(Commit{}, erero.Errorf("cursor %s not found in log selection", cursor))
+		}
+	}
+}
+
+// TrackedFilesSeq streams tracked file paths from git ls-files -z
+// Paths come as raw bytes without quoting, breaking out of the loop kills the git process
+// Use case: scan tracked files of huge repos without holding the full listing
+//
+// TrackedFilesSeq 从 git ls-files -z 流式返回跟踪文件路径
+// 路径为无引号的原始字节，跳出循环会终止 git 进程
+// 使用场景：扫描大仓库的跟踪文件而不持有完整列表
+func (G *Gcm) TrackedFilesSeq() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for record, err := range G.stream(0, "git", "ls-files", "-z") {
+			if err != nil {
+				yield("", erero.Wro(err))
+				return
+			}
+			if !yield(string(record), nil) {
+				return
+			}
+		}
+	}
+}
+
+// stream runs the command and yields stdout records split by delim as they arrive
+// Stdout goes through a pipe into the Runner, so the output never gets buffered in full
+// Stopping the iteration cancels the command context, killing the process group
+// Failures (non-zero exit) come as a final *GitError with the captured stderr
+//
+// stream 运行命令并在数据到达时返回以 delim 分隔的 stdout 记录
+// stdout 通过管道交给 Runner，因此输出永远不会被完整缓存
+// 停止迭代会取消命令上下文并终止进程组
+// 失败（非零退出）以携带捕获 stderr 的 *GitError 作为最后一项返回
+func (G *Gcm) stream(delim byte, name string, args ...string) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		commandCtx, cancel := G.newCommandContext()
+		defer cancel()
+		ctx, stop := context.WithCancel(commandCtx)
+		defer stop()
+
+		command := newCommand(G.execConfig, name, args)
+		if err := ctx.Err(); err != nil {
+			yield(nil, newGitError(command, &Result{ExitCode: -1}, 0, err))
+			return
+		}
+		if G.execConfig.IsShowCommand() {
+			zaplog.SUG.Debugln("EXEC:", command.String())
+		}
+		pipeReader, pipeWriter := io.Pipe()
+		command.Stdout = pipeWriter
+
+		var result *Result
+		var runErr error
+		done := make(chan struct{})
+		startTime := time.Now()
+		go func() {
+			defer close(done)
+			result, runErr = G.runCommand(ctx, command)
+			_ = pipeWriter.Close()
+		}()
+		// Stop the command and wait the goroutine when returning early // 提前返回时停止命令并等待协程结束
+		abort := func() {
+			stop()
+			_ = pipeReader.Close()
+			<-done
+		}
+
+		reader := bufio.NewReader(pipeReader)
+		for {
+			record, err := reader.ReadBytes(delim)
+			if err != nil && err != io.EOF {
+				abort()
+				yield(nil, newGitError(command, &Result{ExitCode: -1}, time.Since(startTime), err))
+				return
+			}
+			// A lone delim is a blank record, zero bytes only happen at EOF // 单独的分隔符是空记录，零字节只出现在 EOF
+			if len(record) > 0 {
+				if !yield(bytes.TrimSuffix(record, []byte{delim}), nil) {
+					abort() // Consumer stopped early, kill git // 消费方提前停止，终止 git
+					return
+				}
+			}
+			if err == io.EOF {
+				break
+			}
+		}
+		<-done
+		if runErr != nil {
+			if ctxErr := commandCtx.Err(); ctxErr != nil {
+				runErr = ctxErr
+			}
+			yield(nil, newGitError(command, result, time.Since(startTime), runErr))
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package gitgo_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_LogSeq tests streamed commits against the buffered Log results
+// Verifies Limit, Skip and After cursor behave the same as in Log
+//
+// TestGcm_LogSeq 测试流式提交与缓冲的 Log 结果一致
+// 验证 Limit、Skip 和 After 游标的行为与 Log 相同
+func TestGcm_LogSeq(t *testing.T) {
+	gcm, _ := newLogTestRepo(t)
+
+	all := rese.V1(gcm.Log(gitgo.LogOptions{}))
+
+	var streamed []gitgo.Commit
+	for commit, err := range gcm.LogSeq(gitgo.LogOptions{}) {
+		require.NoError(t, err)
+		streamed = append(streamed, commit)
+	}
+	require.Equal(t, all, streamed)
+
+	var paged []gitgo.Commit
+	for commit, err := range gcm.LogSeq(gitgo.LogOptions{Limit: 2, Skip: 1, After: all[0].Hash}) {
+		require.NoError(t, err)
+		paged = append(paged, commit)
+	}
+	require.Equal(t, all[2:4], paged)
+}
+
+// TestGcm_LogSeq_Break tests early break out of the streamed log
+// Verifies the loop stops at once and the Gcm stays usable
+//
+// TestGcm_LogSeq_Break 测试提前跳出流式日志
+// 验证循环立即停止且 Gcm 仍可使用
+func TestGcm_LogSeq_Break(t *testing.T) {
+	gcm, _ := newLogTestRepo(t)
+
+	var count int
+	for _, err := range gcm.LogSeq(gitgo.LogOptions{}) {
+		require.NoError(t, err)
+		if count++; count == 2 {
+			break
+		}
+	}
+	require.Equal(t, 2, count)
+	require.NoError(t, gcm.Status().Reason())
+}
+
+// TestGcm_LogSeq_NotARepository tests the streamed error outside a repo
+// Verifies the final item carries the classified GitError
+//
+// TestGcm_LogSeq_NotARepository 测试在仓库外的流式错误
+// 验证最后一项携带已分类的 GitError
+func TestGcm_LogSeq_NotARepository(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-seq-no-repo-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	var errs []error
+	for _, err := range gitgo.New(tempDIR).LogSeq(gitgo.LogOptions{}) {
+		errs = append(errs, err)
+	}
+	require.Len(t, errs, 1)
+	require.True(t, gitgo.IsNotARepository(errs[0]))
+}
+
+// TestGcm_TrackedFilesSeq tests streamed tracked files against GetTrackedFiles
+// Verifies raw names with spaces and early break
+//
+// TestGcm_TrackedFilesSeq 测试流式跟踪文件与 GetTrackedFiles 一致
+// 验证含空格的原始名称和提前跳出
+func TestGcm_TrackedFilesSeq(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-seq-files-*"))
+	t.Cleanup(func() { must.Done(os.RemoveAll(tempDIR)) })
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	for idx := 0; idx < 20; idx++ {
+		must.Done(os.WriteFile(filepath.Join(tempDIR, fmt.Sprintf("file %02d.txt", idx)), []byte("x"), 0644))
+	}
+	gcm.Add().Commit("files").Done()
+
+	var files []string
+	for path, err := range gcm.TrackedFilesSeq() {
+		require.NoError(t, err)
+		files = append(files, path)
+	}
+	require.Len(t, files, 20)
+	require.Equal(t, "file 00.txt", files[0])
+
+	var count int
+	for range gcm.TrackedFilesSeq() {
+		if count++; count == 3 {
+			break
+		}
+	}
+	require.Equal(t, 3, count)
+}
@@ -0,0 +1,275 @@
+package gitgo
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+	"github.com/yyle88/osexistpath"
+)
+
+// Submodule is one typed submodule combining .gitmodules with git submodule status
+//
+// Submodule 是结合 .gitmodules 和 git submodule status 的类型化子模块
+type Submodule struct {
+	Name             string // Submodule name in .gitmodules // .gitmodules 中的子模块名称
+	Path             string // Path relative to the superproject root // 相对于父项目根目录的路径
+	URL              string // URL in .gitmodules // .gitmodules 中的 URL
+	Branch           string // Configured branch, blank when unset // 配置的分支，未设置时为空
+	RecordedCommit   string // Commit recorded in the superproject index // 父项目索引中记录的提交
+	CheckedOutCommit string // Commit checked out in the submodule, blank when not initialized // 子模块中检出的提交，未初始化时为空
+	Initialized      bool   // Submodule is initialized and checked out // 子模块已初始化并检出
+	Conflicted       bool   // Recorded commit has merge conflicts // 记录的提交存在合并冲突
+	Dirty            bool   // Submodule has tracked changes or untracked files // 子模块有跟踪文件更改或未跟踪文件
+	gcm              *Gcm   // Gcm bound to the submodule path // 绑定到子模块路径的 Gcm
+}
+
+// OutOfSync checks if the checked out commit differs from the recorded one
+//
+// OutOfSync 检查检出的提交是否与记录的提交不同
+func (s *Submodule) OutOfSync() bool {
+	return s.Initialized && s.CheckedOutCommit != s.RecordedCommit
+}
+
+// Gcm returns a ready-to-use Gcm bound to the submodule path, sharing the chain settings
+//
+// Gcm 返回绑定到子模块路径、共享链设置的可用 Gcm
+func (s *Submodule) Gcm() *Gcm {
+	return s.gcm
+}
+
+// SubmoduleAdd clones url into path and records it as a submodule
+// Blank branch tracks the remote HEAD, else the branch gets written to .gitmodules
+// Use case: embed shared libraries in the repo
+//
+// SubmoduleAdd 将 url 克隆到 path 并记录为子模块
+// branch 为空时跟踪远程 HEAD，否则将分支写入 .gitmodules
+// 使用场景：在仓库中嵌入共享库
+func (G *Gcm) SubmoduleAdd(url, path, branch string) *Gcm {
+	if branch == "" {
+		return G.do("git", "submodule", "add", "--", url, path)
+	}
+	return G.do("git", "submodule", "add", "-b", branch, "--", url, path)
+}
+
+// SubmoduleInit copies the submodule URLs of .gitmodules into the repo config
+// Run SubmoduleUpdate next to check out the submodules
+//
+// SubmoduleInit 将 .gitmodules 中的子模块 URL 复制到仓库配置中
+// 之后运行 SubmoduleUpdate 检出子模块
+func (G *Gcm) SubmoduleInit() *Gcm {
+	return G.do("git", "submodule", "init")
+}
+
+// SubmoduleUpdate checks out the recorded commits of initialized submodules
+// Recursive also updates nested submodules, remote checks out the tip of the configured branch instead
+// Use case: bring submodules in line after Clone, Pull and Checkout
+//
+// SubmoduleUpdate 检出已初始化子模块中记录的提交
+// recursive 同时更新嵌套子模块，remote 改为检出配置分支的最新提交
+// 使用场景：在 Clone、Pull 和 Checkout 后同步子模块
+func (G *Gcm) SubmoduleUpdate(recursive, remote bool) *Gcm {
+	args := []string{"submodule", "update"}
+	if recursive {
+		args = append(args, "--recursive")
+	}
+	if remote {
+		args = append(args, "--remote")
+	}
+	return G.do("git", args...)
+}
+
+// SubmoduleSync copies changed submodule URLs of .gitmodules into the config
+// Use case: follow submodules moved to new hosts
+//
+// SubmoduleSync 将 .gitmodules 中已更改的子模块 URL 复制到配置中
+// 使用场景：跟随迁移到新主机的子模块
+func (G *Gcm) SubmoduleSync() *Gcm {
+	return G.do("git", "submodule", "sync")
+}
+
+// SubmoduleDeinit unregisters the submodule at path and empties its worktree
+// Fails when the submodule has local changes
+//
+// SubmoduleDeinit 注销 path 处的子模块并清空其工作区
+// 子模块有本地更改时失败
+func (G *Gcm) SubmoduleDeinit(path string) *Gcm {
+	return G.do("git", "submodule", "deinit", "--", path)
+}
+
+// ListSubmodules gets the typed submodules of .gitmodules, following its sequence
+// Returns name, path, URL, branch, recorded and checked out commits and dirty state
+// Each entry carries a Gcm bound to its path, blank list when the repo has no .gitmodules
+// Use case: audit submodule pins before releases
+//
+// ListSubmodules 按 .gitmodules 中的顺序获取类型化的子模块
+// 返回名称、路径、URL、分支、记录的和检出的提交以及脏状态
+// 每个条目携带绑定到其路径的 Gcm，仓库没有 .gitmodules 时返回空列表
+// 使用场景：在发布前审查子模块的固定版本
+func (G *Gcm) ListSubmodules() ([]Submodule, error) {
+	topPath, err := G.GetTopPath()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	gitmodulesExists, err := osexistpath.IsFile(filepath.Join(topPath, ".gitmodules"))
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if !gitmodulesExists {
+		return nil, nil
+	}
+	// Run at the root so paths do not depend on the sub path // 在根目录运行，使路径不依赖子路径
+	top := G.newPathGcm(topPath)
+
+	output, exc, err := top.execTake(top.execConfig.NewConfig().WithExpectExit(1, "NO-SUBMODULES"), "git", "config", "-z", "--file", ".gitmodules", "--get-regexp", `^submodule\.`)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	if exc == 1 {
+		return nil, nil
+	}
+	submodules := parseGitmodules(output)
+
+	output, err = top.execStdout("git", "ls-files", "--stage", "-z")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	recorded := parseGitlinks(output)
+
+	output, err = top.execStdout("git", "submodule", "status")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	statusLines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+
+	status, err := top.GetStatus()
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+
+	for idx := range submodules {
+		submodule := &submodules[idx]
+		submodule.RecordedCommit = recorded[submodule.Path]
+		for _, line := range statusLines {
+			flag, hash, ok := matchSubmoduleStatus(line, submodule.Path)
+			if !ok {
+				continue
+			}
+			switch flag {
+			case ' ', '+':
+				submodule.Initialized, submodule.CheckedOutCommit = true, hash
+			case 'U':
+				submodule.Conflicted = true
+			}
+		}
+		for _, entry := range status.Entries {
+			if entry.Path == submodule.Path && entry.Submodule.IsSubmodule {
+				submodule.Dirty = entry.Submodule.TrackedChanges || entry.Submodule.UntrackedExists
+			}
+		}
+		submodule.gcm = G.newPathGcm(filepath.Join(topPath, submodule.Path))
+	}
+	return submodules, nil
+}
+
+// ForEachSubmodule runs the body on a Gcm bound to each initialized submodule, in .gitmodules sequence
+// Stops at the first failing body, wrapping its error with the submodule path
+// Use case: run the same git steps across embedded repos
+//
+// ForEachSubmodule 按 .gitmodules 顺序在绑定到每个已初始化子模块的 Gcm 上运行 body
+// 在第一个失败的 body 处停止，并用子模块路径包装其错误
+// 使用场景：在嵌入的仓库中运行相同的 git 步骤
+func (G *Gcm) ForEachSubmodule(run func(*Gcm) error) *Gcm {
+	if G.errorOnce != nil {
+		return G // Short-circuit: halt execution on existing errors // 短路：存在错误时停止执行
+	}
+	submodules, err := G.ListSubmodules()
+	if err != nil {
+		return newWaGcm(G.execConfig, G.options, []byte{}, err, G.debugMode)
+	}
+	for idx := range submodules {
+		if !submodules[idx].Initialized {
+			continue
+		}
+		if err := run(submodules[idx].Gcm()); err != nil {
+			return newWaGcm(G.execConfig, G.options, []byte{}, erero.Wrapf(err, "submodule %s", submodules[idx].Path), G.debugMode)
+		}
+	}
+	return G
+}
+
+// parseGitmodules parses git config -z output of submodule.<name>.<key> entries, keeping first-seen sequence
+// Names may contain dots, so the key is cut at the last dot
+//
+// parseGitmodules 解析 submodule.<name>.<key> 条目的 git config -z 输出，保持首次出现的顺序
+// 名称可能包含点，因此在最后一个点处切分键
+func parseGitmodules(output []byte) []Submodule {
+	var submodules []Submodule
+	var positions = map[string]int{}
+	for _, record := range bytes.Split(output, []byte{0}) {
+		if len(record) == 0 {
+			continue
+		}
+		key, value, _ := strings.Cut(string(record), "\n")
+		nameKey := strings.TrimPrefix(key, "submodule.")
+		dot := strings.LastIndexByte(nameKey, '.')
+		if dot < 0 {
+			continue
+		}
+		name := nameKey[:dot]
+		position, ok := positions[name]
+		if !ok {
+			position = len(submodules)
+			positions[name] = position
+			submodules = append(submodules, Submodule{Name: name})
+		}
+		switch nameKey[dot+1:] {
+		case "path":
+			submodules[position].Path = value
+		case "url":
+			submodules[position].URL = value
+		case "branch":
+			submodules[position].Branch = value
+		}
+	}
+	return submodules
+}
+
+// parseGitlinks maps paths to commits of the gitlink (mode 160000) entries of git ls-files --stage -z
+//
+// parseGitlinks 将 git ls-files --stage -z 中 gitlink（模式 160000）条目的路径映射到提交
+func parseGitlinks(output []byte) map[string]string {
+	var gitlinks = map[string]string{}
+	for _, record := range bytes.Split(output, []byte{0}) {
+		// Record format: "<mode> <hash> <stage>\t<path>" // 记录格式："<mode> <hash> <stage>\t<path>"
+		info, path, ok := strings.Cut(string(record), "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(info)
+		if len(fields) == 3 && fields[0] == "160000" && fields[2] == "0" {
+			gitlinks[path] = fields[1]
+		}
+	}
+	return gitlinks
+}
+
+// matchSubmoduleStatus matches a git submodule status line of the path
+// Line format: "<flag><hash> <path>" with an optional " (<describe>)" suffix
+//
+// matchSubmoduleStatus 匹配 path 对应的 git submodule status 行
+// 行格式："<flag><hash> <path>"，可带 " (<describe>)" 后缀
+func matchSubmoduleStatus(line, path string) (byte, string, bool) {
+	if len(line) < 2 {
+		return 0, "", false
+	}
+	hash, rest, ok := strings.Cut(line[1:], " ")
+	if !ok {
+		return 0, "", false
+	}
+	if rest != path && !strings.HasPrefix(rest, path+" (") {
+		return 0, "", false
+	}
+	return line[0], hash, true
+}
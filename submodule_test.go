@@ -0,0 +1,101 @@
+package gitgo_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Submodule tests adding, listing, updating, syncing and deinit of submodules
+// Verifies recorded vs checked out commits, dirty state and ForEachSubmodule
+//
+// TestGcm_Submodule 测试子模块的添加、列出、更新、同步和注销
+// 验证记录的与检出的提交、脏状态以及 ForEachSubmodule
+func TestGcm_Submodule(t *testing.T) {
+	// Local path URLs need the file protocol since git 2.38.1 // 自 git 2.38.1 起本地路径 URL 需要允许 file 协议
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-submodule-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	libPath := filepath.Join(tempDIR, "lib")
+	superPath := filepath.Join(tempDIR, "super")
+	must.Done(os.MkdirAll(libPath, 0755))
+	must.Done(os.MkdirAll(superPath, 0755))
+
+	lib := gitgo.New(libPath)
+	lib.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(libPath, "lib.txt"), []byte("v1"), 0644))
+	lib.Add().Commit("lib v1").Done()
+	v1 := rese.V1(lib.GetCurrentCommitHash())
+	must.Done(os.WriteFile(filepath.Join(libPath, "lib.txt"), []byte("v2"), 0644))
+	lib.Add().Commit("lib v2").Done()
+	v2 := rese.V1(lib.GetCurrentCommitHash())
+
+	gcm := gitgo.New(superPath)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(superPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	require.Empty(t, rese.V1(gcm.ListSubmodules()))
+
+	gcm.SubmoduleAdd(libPath, "libs/lib", "main").Commit("add lib").Done()
+	submodules := rese.V1(gcm.ListSubmodules())
+	require.Len(t, submodules, 1)
+	require.Equal(t, "libs/lib", submodules[0].Name)
+	require.Equal(t, "libs/lib", submodules[0].Path)
+	require.Equal(t, libPath, submodules[0].URL)
+	require.Equal(t, "main", submodules[0].Branch)
+	require.Equal(t, v2, submodules[0].RecordedCommit)
+	require.Equal(t, v2, submodules[0].CheckedOutCommit)
+	require.True(t, submodules[0].Initialized)
+	require.False(t, submodules[0].OutOfSync())
+	require.False(t, submodules[0].Dirty)
+
+	submodules[0].Gcm().Checkout(v1).Done()
+	must.Done(os.WriteFile(filepath.Join(superPath, "libs", "lib", "new.txt"), []byte("new"), 0644))
+	submodules = rese.V1(gitgo.New(filepath.Join(superPath, "libs")).ListSubmodules())
+	require.Equal(t, v2, submodules[0].RecordedCommit)
+	require.Equal(t, v1, submodules[0].CheckedOutCommit)
+	require.True(t, submodules[0].OutOfSync())
+	require.True(t, submodules[0].Dirty)
+
+	var hashes []string
+	gcm.ForEachSubmodule(func(sub *gitgo.Gcm) error {
+		hashes = append(hashes, rese.V1(sub.GetCurrentCommitHash()))
+		return nil
+	}).Done()
+	require.Equal(t, []string{v1}, hashes)
+	require.ErrorContains(t, gcm.ForEachSubmodule(func(sub *gitgo.Gcm) error {
+		return errors.New("boom")
+	}).Reason(), "submodule libs/lib")
+
+	must.Done(os.Remove(filepath.Join(superPath, "libs", "lib", "new.txt")))
+	gcm.SubmoduleUpdate(false, false).SubmoduleSync().Done()
+	submodules = rese.V1(gcm.ListSubmodules())
+	require.False(t, submodules[0].OutOfSync())
+	require.False(t, submodules[0].Dirty)
+
+	gcm.SubmoduleDeinit("libs/lib").Done()
+	submodules = rese.V1(gcm.ListSubmodules())
+	require.False(t, submodules[0].Initialized)
+	require.Empty(t, submodules[0].CheckedOutCommit)
+	require.Equal(t, v2, submodules[0].RecordedCommit)
+
+	must.Done(os.WriteFile(filepath.Join(libPath, "lib.txt"), []byte("v3"), 0644))
+	lib.Add().Commit("lib v3").Done()
+	v3 := rese.V1(lib.GetCurrentCommitHash())
+	gcm.SubmoduleInit().SubmoduleUpdate(true, true).Done()
+	submodules = rese.V1(gcm.ListSubmodules())
+	require.True(t, submodules[0].Initialized)
+	require.Equal(t, v3, submodules[0].CheckedOutCommit)
+	require.True(t, submodules[0].OutOfSync())
+}
@@ -0,0 +1,201 @@
+package gitgo
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/yyle88/erero"
+)
+
+// tagInfoFormat is the for-each-ref --format of ListTagInfos, fields ended by NUL
+// Messages span lines, so records get split by field count, each after the first starts with the newline
+//
+// tagInfoFormat 是 ListTagInfos 使用的 for-each-ref --format 格式，字段以 NUL 结尾
+// 消息可跨多行，因此按字段数拆分记录，第一条之后的每条记录以换行开头
+const tagInfoFormat = "%(refname)%00%(objecttype)%00%(objectname)%00%(*objectname)%00%(taggername)%00%(taggeremail:trim)%00%(creatordate:iso-strict)%00%(contents)%00%(contents:signature)%00"
+
+// tagInfoFieldCount is the count of NUL ended fields in each record of tagInfoFormat
+//
+// tagInfoFieldCount 是 tagInfoFormat 中每条记录以 NUL 结尾的字段数量
+const tagInfoFieldCount = 9
+
+// TagType tells whether a tag is a plain ref or a tag object
+//
+// TagType 表示标签是普通引用还是标签对象
+type TagType string
+
+const (
+	TagLightweight TagType = "lightweight" // Ref pointing at the target directly // 直接指向目标的引用
+	TagAnnotated   TagType = "annotated"   // Tag object with tagger, date and message // 带有标记者、日期和消息的标签对象
+)
+
+// TagInfo is one typed tag with its target and tag object metadata
+// Tagger, email and message stay blank on lightweight tags
+//
+// TagInfo 是一个带有目标和标签对象元数据的类型化标签
+// 轻量标签的标记者、邮箱和消息保持为空
+type TagInfo struct {
+	Name        string    // Tag name like "v1.0.0" // 标签名称，如 "v1.0.0"
+	Type        TagType   // Lightweight or annotated // 轻量或附注
+	Object      string    // Object the ref points at, the tag object on annotated tags // 引用指向的对象，附注标签为标签对象
+	Target      string    // Tagged object, usually a commit // 被标记的对象，通常是提交
+	Tagger      string    // Tagger name // 标记者名称
+	TaggerEmail string    // Tagger email without angle brackets // 不带尖括号的标记者邮箱
+	Date        time.Time // Tagger date, or the commit date on lightweight tags // 标记日期，轻量标签为提交日期
+	Message     string    // Tag message without signature // 不含签名的标签消息
+	Signed      bool      // Tag object carries a signature // 标签对象带有签名
+}
+
+// TagAnnotated creates an annotated tag with message at target, blank target means HEAD
+// Use case: mark releases with notes visible in git show and describe
+//
+// TagAnnotated 在 target 处创建带消息的附注标签，target 为空表示 HEAD
+// 使用场景：标记带有说明的发布，说明可在 git show 和 describe 中看到
+func (G *Gcm) TagAnnotated(name, message, target string) *Gcm {
+	return G.do("git", withTarget([]string{"tag", "-a", name, "-m", message}, target)...)
+}
+
+// TagSigned creates a signed annotated tag with message at target, blank target means HEAD
+// Signs with the configured user.signingKey and gpg.format
+// Use case: publish verifiable releases
+//
+// TagSigned 在 target 处创建带消息的签名附注标签，target 为空表示 HEAD
+// 使用配置的 user.signingKey 和 gpg.format 签名
+// 使用场景：发布可验证的版本
+func (G *Gcm) TagSigned(name, message, target string) *Gcm {
+	return G.do("git", withTarget([]string{"tag", "-s", name, "-m", message}, target)...)
+}
+
+// TagForce creates or moves the lightweight tag to target, blank target means HEAD
+// Use case: move floating tags like "latest" and "v1"
+//
+// TagForce 创建轻量标签或将其移动到 target，target 为空表示 HEAD
+// 使用场景：移动 "latest" 和 "v1" 这类浮动标签
+func (G *Gcm) TagForce(name, target string) *Gcm {
+	return G.do("git", withTarget([]string{"tag", "-f", name}, target)...)
+}
+
+// TagDelete deletes the local tag
+//
+// TagDelete 删除本地标签
+func (G *Gcm) TagDelete(name string) *Gcm {
+	return G.do("git", "tag", "-d", name)
+}
+
+// TagDeleteRemote deletes the tag on the remote, the local tag stays
+// Uses the full ref so branches with the same name stay untouched
+//
+// TagDeleteRemote 删除远程上的标签，本地标签保留
+// 使用完整引用，因此同名分支不受影响
+func (G *Gcm) TagDeleteRemote(remote, name string) *Gcm {
+	return G.do("git", "push", remote, "--delete", "refs/tags/"+name)
+}
+
+// ListTagInfos gets typed tags sorted by creation date, oldest first
+// Returns name, type, target, tagger, date and message of each tag
+// Use case: examine releases to choose the next version, instead of the GetSortedTags text
+//
+// ListTagInfos 获取按创建日期排序的类型化标签，最早的在前
+// 返回每个标签的名称、类型、目标、标记者、日期和消息
+// 使用场景：检查发布以选择下一个版本，替代 GetSortedTags 的文本
+func (G *Gcm) ListTagInfos() ([]TagInfo, error) {
+	output, err := G.execStdout("git", "for-each-ref", "--sort=creatordate", "--format="+tagInfoFormat, "refs/tags")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	return parseTagInfos(output)
+}
+
+// parseTagInfos parses the NUL ended fields of tagInfoFormat into tag infos
+//
+// parseTagInfos 将 tagInfoFormat 以 NUL 结尾的字段解析为标签信息
+func parseTagInfos(output []byte) ([]TagInfo, error) {
+	fields := bytes.Split(output, []byte{0})
+	// Output ends with NUL and newline, leaving one tail item // 输出以 NUL 和换行结尾，留下一个尾项
+	fields = fields[:len(fields)-1]
+	if len(fields)%tagInfoFieldCount != 0 {
+		return nil, erero.Errorf("wrong tag info field count %d", len(fields))
+	}
+	infos := make([]TagInfo, 0, len(fields)/tagInfoFieldCount)
+	for idx := 0; idx < len(fields); idx += tagInfoFieldCount {
+		record := fields[idx : idx+tagInfoFieldCount]
+		date, err := time.Parse(time.RFC3339, string(record[6]))
+		if err != nil {
+			return nil, erero.Wro(err)
+		}
+		info := TagInfo{
+			Name:   strings.TrimPrefix(strings.TrimPrefix(string(record[0]), "\n"), "refs/tags/"),
+			Type:   TagLightweight,
+			Object: string(record[2]),
+			Target: string(record[2]),
+			Date:   date,
+		}
+		if string(record[1]) == "tag" {
+			signature := string(record[8])
+			info.Type = TagAnnotated
+			info.Target = string(record[3])
+			info.Tagger = string(record[4])
+			info.TaggerEmail = string(record[5])
+			info.Message = strings.TrimSpace(strings.TrimSuffix(string(record[7]), signature))
+			info.Signed = signature != ""
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// TagQuery selects and sorts the tags of ListTags, zero value lists each tag by name
+//
+// TagQuery 选择并排序 ListTags 的标签，零值表示按名称列出所有标签
+type TagQuery struct {
+	Patterns []string // Glob patterns, a tag matching any of them is listed // glob 模式，匹配任一模式的标签会被列出
+	Contains string   // List tags containing this commit (--contains) // 列出包含此提交的标签（--contains）
+	Merged   string   // List tags reachable from this commit (--merged) // 列出可从此提交到达的标签（--merged）
+	PointsAt string   // List tags pointing at this object (--points-at) // 列出指向此对象的标签（--points-at）
+	Sort     []string // Sort keys like "-version:refname" and "creatordate", the last key is primary (--sort) // 排序键，如 "-version:refname" 和 "creatordate"，最后一个键为主键（--sort）
+}
+
+// ListTags gets tag names selected and sorted by the query, using argv execution without shell
+// Use case: find release tags of a branch or commit with custom ordering
+//
+// ListTags 获取按查询选择和排序的标签名称，使用 argv 执行而不经过 shell
+// 使用场景：按自定义顺序查找某分支或提交的发布标签
+func (G *Gcm) ListTags(query TagQuery) ([]string, error) {
+	args := []string{"tag", "--list"}
+	for _, key := range query.Sort {
+		args = append(args, "--sort="+key)
+	}
+	if query.Contains != "" {
+		args = append(args, "--contains", query.Contains)
+	}
+	if query.Merged != "" {
+		args = append(args, "--merged", query.Merged)
+	}
+	if query.PointsAt != "" {
+		args = append(args, "--points-at", query.PointsAt)
+	}
+	args = append(args, "--")
+	args = append(args, query.Patterns...)
+	output, err := G.execStdout("git", args...)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	var tags []string
+	for _, tag := range strings.Split(string(output), "\n") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// withTarget appends target to args when given
+//
+// withTarget 在给出 target 时将其追加到 args
+func withTarget(args []string, target string) []string {
+	if target == "" {
+		return args
+	}
+	return append(args, target)
+}
@@ -0,0 +1,163 @@
+package gitgo_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_TagInfos tests annotated, forced and deleted tags with typed tag listing
+// Verifies lightweight and annotated metadata and remote tag deletion
+//
+// TestGcm_TagInfos 测试附注、强制和删除标签以及类型化标签列表
+// 验证轻量和附注标签的元数据以及远程标签删除
+func TestGcm_TagInfos(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-tag-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	repoPath := filepath.Join(tempDIR, "repo")
+	must.Done(os.MkdirAll(repoPath, 0755))
+
+	gcm := gitgo.New(repoPath)
+	gcm.Init().Done()
+	require.Empty(t, rese.V1(gcm.ListTagInfos()))
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+	first := rese.V1(gcm.GetCurrentCommitHash())
+	t.Setenv("GIT_COMMITTER_DATE", "2030-01-01T00:00:00Z")
+	must.Done(os.WriteFile(filepath.Join(repoPath, "b.txt"), []byte("b"), 0644))
+	gcm.Add().Commit("second").Done()
+	second := rese.V1(gcm.GetCurrentCommitHash())
+
+	gcm.Tag("light").Done()
+	t.Setenv("GIT_COMMITTER_DATE", "2031-01-01T00:00:00Z")
+	gcm.TagAnnotated("v1.0.0", "release v1.0.0\n\nfirst stable", first).Done()
+	require.Error(t, gcm.TagAnnotated("v1.0.0", "again", "").Reason())
+
+	infos := rese.V1(gcm.ListTagInfos())
+	require.Len(t, infos, 2)
+	light, annotated := infos[0], infos[1]
+	require.Equal(t, "v1.0.0", annotated.Name)
+	require.Equal(t, gitgo.TagAnnotated, annotated.Type)
+	require.Equal(t, first, annotated.Target)
+	require.NotEqual(t, first, annotated.Object)
+	require.NotEmpty(t, annotated.Tagger)
+	require.NotEmpty(t, annotated.TaggerEmail)
+	require.NotContains(t, annotated.TaggerEmail, "<")
+	require.Equal(t, 2031, annotated.Date.Year())
+	require.Equal(t, "release v1.0.0\n\nfirst stable", annotated.Message)
+	require.False(t, annotated.Signed)
+
+	require.Equal(t, "light", light.Name)
+	require.Equal(t, gitgo.TagLightweight, light.Type)
+	require.Equal(t, second, light.Target)
+	require.Equal(t, second, light.Object)
+	require.Empty(t, light.Tagger)
+	require.Empty(t, light.Message)
+	require.Equal(t, 2030, light.Date.Year())
+
+	gcm.TagForce("light", first).Done()
+	require.Equal(t, first, rese.V1(gcm.GetCommitHash("light")))
+
+	remotePath := filepath.Join(tempDIR, "remote.git")
+	rese.P1(gitgo.Clone(repoPath, remotePath, gitgo.CloneOptions{Bare: true}))
+	gcm.RemoteAdd("origin", remotePath).TagDelete("light").Done()
+	require.False(t, rese.V1(gcm.TagExists("light")))
+
+	remote := gitgo.New(remotePath)
+	require.True(t, rese.V1(remote.TagExists("light")))
+	gcm.TagDeleteRemote("origin", "light").Done()
+	require.False(t, rese.V1(remote.TagExists("light")))
+	require.True(t, rese.V1(remote.TagExists("v1.0.0")))
+}
+
+// TestGcm_TagSigned tests signed tags using an ssh signing key
+// Verifies the signature gets split off the message
+//
+// TestGcm_TagSigned 测试使用 ssh 签名密钥的签名标签
+// 验证签名会从消息中分离
+func TestGcm_TagSigned(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not available")
+	}
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-tag-signed-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	keyPath := filepath.Join(tempDIR, "key")
+	must.Done(exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).Run())
+	t.Setenv("GIT_CONFIG_COUNT", "2")
+	t.Setenv("GIT_CONFIG_KEY_0", "gpg.format")
+	t.Setenv("GIT_CONFIG_VALUE_0", "ssh")
+	t.Setenv("GIT_CONFIG_KEY_1", "user.signingKey")
+	t.Setenv("GIT_CONFIG_VALUE_1", keyPath+".pub")
+
+	repoPath := filepath.Join(tempDIR, "repo")
+	must.Done(os.MkdirAll(repoPath, 0755))
+	gcm := gitgo.New(repoPath)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").TagSigned("v1.0.0", "signed release", "").Done()
+
+	infos := rese.V1(gcm.ListTagInfos())
+	require.Len(t, infos, 1)
+	require.Equal(t, gitgo.TagAnnotated, infos[0].Type)
+	require.True(t, infos[0].Signed)
+	require.Equal(t, "signed release", infos[0].Message)
+}
+
+// TestGcm_ListTags tests tag queries with globs, commit filters and sort keys
+// Verifies latest tag lookups run without shell, taking unsafe chars as plain text
+//
+// TestGcm_ListTags 测试带有 glob、提交过滤和排序键的标签查询
+// 验证最新标签查找不经过 shell，将不安全字符作为纯文本处理
+func TestGcm_ListTags(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-list-tags-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+
+	gcm := gitgo.New(tempDIR)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Tag("v1.9.0").Tag("auth/v1.0.0").Done()
+	first := rese.V1(gcm.GetCurrentCommitHash())
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "b.txt"), []byte("b"), 0644))
+	gcm.Add().Commit("second").Tag("v1.10.0").Tag("auth/v1.1.0").Done()
+	gcm.CheckoutNewBranch("feature").Done()
+	must.Done(os.WriteFile(filepath.Join(tempDIR, "c.txt"), []byte("c"), 0644))
+	gcm.Add().Commit("feature work").Tag("v2.0.0-rc.1").Checkout("main").Done()
+
+	require.Equal(t, []string{"auth/v1.0.0", "auth/v1.1.0", "v1.10.0", "v1.9.0", "v2.0.0-rc.1"}, rese.V1(gcm.ListTags(gitgo.TagQuery{})))
+	require.Equal(t, []string{"v2.0.0-rc.1", "v1.10.0", "v1.9.0"}, rese.V1(gcm.ListTags(gitgo.TagQuery{Patterns: []string{"v1.*", "v2.*"}, Sort: []string{"-version:refname"}})))
+	require.Equal(t, []string{"auth/v1.1.0", "v1.10.0", "v2.0.0-rc.1"}, rese.V1(gcm.ListTags(gitgo.TagQuery{Contains: "v1.10.0"})))
+	require.Equal(t, []string{"auth/v1.0.0", "auth/v1.1.0", "v1.10.0", "v1.9.0"}, rese.V1(gcm.ListTags(gitgo.TagQuery{Merged: "main"})))
+	require.Equal(t, []string{"auth/v1.0.0", "v1.9.0"}, rese.V1(gcm.ListTags(gitgo.TagQuery{PointsAt: first})))
+	require.Empty(t, rese.V1(gcm.ListTags(gitgo.TagQuery{Patterns: []string{"release-*"}})))
+	_, err := gcm.ListTags(gitgo.TagQuery{Contains: "missing"})
+	require.Error(t, err)
+
+	require.Equal(t, "v2.0.0-rc.1", rese.C1(gcm.GetLatestTagHasPrefix("v")))
+	require.Equal(t, "auth/v1.1.0", rese.C1(gcm.GetLatestTagHasPrefix("auth/")))
+	require.Equal(t, "v1.10.0", rese.C1(gcm.GetLatestTagMatchGlob("v1.*")))
+	require.Empty(t, rese.V1(gcm.GetLatestTagHasPrefix("v*")))
+	require.Empty(t, rese.V1(gcm.GetLatestTagHasPrefix("v'; touch pwned; '")))
+	require.Empty(t, rese.V1(gcm.GetLatestTagMatchGlob("v$(touch pwned)\n*")))
+	require.NoFileExists(t, filepath.Join(tempDIR, "pwned"))
+
+	// SemVer precedence ranks releases above their prereleases, unlike version:refname
+	// SemVer 优先级使正式版本高于其预发布版本，不同于 version:refname
+	gcm.Tag("v2.0.0").Tag("vnext").Tag("auth/v1.1.0-rc.1").Tag("release-a").Tag("release-b").Done()
+	require.Equal(t, "v2.0.0", rese.C1(gcm.GetLatestTagHasPrefix("v")))
+	require.Equal(t, "v2.0.0", rese.C1(gcm.GetLatestTagMatchGlob("v2.*")))
+	require.Equal(t, "auth/v1.1.0", rese.C1(gcm.GetLatestTagHasPrefix("auth/")))
+	require.Equal(t, "auth/v1.1.0", rese.C1(gcm.GetLatestTagMatchGlob("auth/*")))
+	require.Equal(t, "release-b", rese.C1(gcm.GetLatestTagMatchGlob("release-*")))
+}
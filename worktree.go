@@ -0,0 +1,196 @@
+package gitgo
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/yyle88/erero"
+)
+
+// WorktreeAddOptions tunes WorktreeAdd, zero value checks out the branch as it is
+//
+// WorktreeAddOptions 调整 WorktreeAdd 的行为，零值表示按原样检出分支
+type WorktreeAddOptions struct {
+	NewBranch  string // Create this branch starting at the given commit-ish (-b) // 从给定的 commit-ish 创建此分支（-b）
+	Detach     bool   // Check out a detached HEAD (--detach) // 检出游离 HEAD（--detach）
+	Force      bool   // Allow a branch already checked out elsewhere (--force) // 允许已在其他位置检出的分支（--force）
+	Lock       bool   // Lock the new worktree (--lock) // 锁定新工作树（--lock）
+	LockReason string // Lock reason, needs Lock // 锁定原因，需要 Lock
+	NoCheckout bool   // Skip populating files (--no-checkout) // 不填充文件（--no-checkout）
+}
+
+// Worktree is one typed entry of git worktree list --porcelain
+//
+// Worktree 是 git worktree list --porcelain 的一个类型化条目
+type Worktree struct {
+	Path           string // Absolute worktree path // 工作树的绝对路径
+	Head           string // HEAD commit hash, blank on bare repos // HEAD 提交哈希，裸仓库为空
+	Branch         string // Checked out branch short name, blank when detached or bare // 检出的分支短名，游离或裸仓库时为空
+	Bare           bool   // Main worktree of a bare repo // 裸仓库的主工作树
+	Detached       bool   // HEAD is detached // HEAD 处于游离状态
+	Locked         bool   // Worktree is locked // 工作树已锁定
+	LockReason     string // Lock reason, blank when given none // 锁定原因，未给出时为空
+	Prunable       bool   // Worktree can be pruned, like when its path is gone // 工作树可被清理，如其路径已不存在
+	PrunableReason string // Why the worktree can be pruned // 工作树可被清理的原因
+	gcm            *Gcm   // Gcm bound to the worktree path // 绑定到工作树路径的 Gcm
+}
+
+// Gcm returns a ready-to-use Gcm bound to the worktree path, sharing the chain settings
+//
+// Gcm 返回绑定到工作树路径、共享链设置的可用 Gcm
+func (w *Worktree) Gcm() *Gcm {
+	return w.gcm
+}
+
+// WorktreeAdd checks out commitish (branch, tag or hash) into a new worktree at path
+// Returns a Gcm bound to the new worktree, relative paths are resolved from the repo path
+// Use case: run parallel CI jobs on many branches of one repo without separate clones
+//
+// WorktreeAdd 将 commitish（分支、标签或哈希）检出到 path 处的新工作树
+// 返回绑定到新工作树的 Gcm，相对路径基于仓库路径解析
+// 使用场景：在一个仓库的多个分支上并行运行 CI 任务而无需多次克隆
+func (G *Gcm) WorktreeAdd(path, commitish string, opts WorktreeAddOptions) *Gcm {
+	args := []string{"worktree", "add"}
+	if opts.NewBranch != "" {
+		args = append(args, "-b", opts.NewBranch)
+	}
+	if opts.Detach {
+		args = append(args, "--detach")
+	}
+	if opts.Force {
+		args = append(args, "--force")
+	}
+	if opts.Lock {
+		args = append(args, "--lock")
+		if opts.LockReason != "" {
+			args = append(args, "--reason", opts.LockReason)
+		}
+	}
+	if opts.NoCheckout {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, "--", path)
+	if commitish != "" {
+		args = append(args, commitish)
+	}
+	res := G.do("git", args...)
+	if res.errorOnce != nil {
+		return res
+	}
+	return G.newPathGcm(path)
+}
+
+// WorktreeRemove removes the worktree at path, failing when it has changes
+//
+// WorktreeRemove 删除 path 处的工作树，有更改时失败
+func (G *Gcm) WorktreeRemove(path string) *Gcm {
+	return G.do("git", "worktree", "remove", "--", path)
+}
+
+// WorktreeRemoveForce removes the worktree at path, discarding its changes and untracked files
+//
+// WorktreeRemoveForce 删除 path 处的工作树，丢弃其更改和未跟踪文件
+func (G *Gcm) WorktreeRemoveForce(path string) *Gcm {
+	return G.do("git", "worktree", "remove", "--force", "--", path)
+}
+
+// WorktreePrune cleans up administrative files of worktrees whose paths are gone
+// Use case: tidy up after CI runners deleted job directories
+//
+// WorktreePrune 清理路径已不存在的工作树的管理文件
+// 使用场景：在 CI 运行器删除任务目录后进行整理
+func (G *Gcm) WorktreePrune() *Gcm {
+	return G.do("git", "worktree", "prune")
+}
+
+// WorktreeLock locks the worktree at path against pruning, moving and removing
+// Blank reason locks without reason
+//
+// WorktreeLock 锁定 path 处的工作树，防止其被清理、移动和删除
+// reason 为空时不带原因锁定
+func (G *Gcm) WorktreeLock(path, reason string) *Gcm {
+	if reason == "" {
+		return G.do("git", "worktree", "lock", "--", path)
+	}
+	return G.do("git", "worktree", "lock", "--reason", reason, "--", path)
+}
+
+// WorktreeUnlock unlocks the worktree at path
+//
+// WorktreeUnlock 解锁 path 处的工作树
+func (G *Gcm) WorktreeUnlock(path string) *Gcm {
+	return G.do("git", "worktree", "unlock", "--", path)
+}
+
+// ListWorktrees gets typed entries of the worktrees, the main worktree first
+// Each entry carries a Gcm bound to its path
+// Use case: find free worktrees to reuse and stale ones to prune
+//
+// ListWorktrees 获取工作树的类型化条目，主工作树在前
+// 每个条目携带绑定到其路径的 Gcm
+// 使用场景：查找可复用的空闲工作树和需要清理的过期工作树
+func (G *Gcm) ListWorktrees() ([]Worktree, error) {
+	output, err := G.execStdout("git", "worktree", "list", "--porcelain", "-z")
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	worktrees, err := parseWorktreeList(output)
+	if err != nil {
+		return nil, erero.Wro(err)
+	}
+	for idx := range worktrees {
+		worktrees[idx].gcm = G.newPathGcm(worktrees[idx].Path)
+	}
+	return worktrees, nil
+}
+
+// newPathGcm creates a Gcm bound to the path sharing the chain settings
+// Relative paths are resolved from the repo path, like git does
+//
+// newPathGcm 创建绑定到该路径并共享链设置的 Gcm
+// 相对路径像 git 一样基于仓库路径解析
+func (G *Gcm) newPathGcm(path string) *Gcm {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(G.execConfig.Path, path)
+	}
+	return newOkGcm(G.execConfig.SubConfig(path), G.options, make([]byte, 0), G.debugMode)
+}
+
+// parseWorktreeList parses NUL separated porcelain worktree records, ended by a blank field
+//
+// parseWorktreeList 解析以 NUL 分隔的 porcelain 工作树记录，记录以空字段结尾
+func parseWorktreeList(output []byte) ([]Worktree, error) {
+	var worktrees []Worktree
+	var current *Worktree
+	for _, field := range bytes.Split(output, []byte{0}) {
+		if len(field) == 0 {
+			current = nil // Blank field ends the record // 空字段结束记录
+			continue
+		}
+		key, value, _ := strings.Cut(string(field), " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+			continue
+		}
+		if current == nil {
+			return nil, erero.Errorf("worktree field %q before worktree path", key)
+		}
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked, current.LockReason = true, value
+		case "prunable":
+			current.Prunable, current.PrunableReason = true, value
+		}
+	}
+	return worktrees, nil
+}
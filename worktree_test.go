@@ -0,0 +1,81 @@
+package gitgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-xlan/gitgo"
+	"github.com/stretchr/testify/require"
+	"github.com/yyle88/must"
+	"github.com/yyle88/rese"
+)
+
+// TestGcm_Worktree tests adding, listing, locking, removing and pruning worktrees
+// Verifies the returned Gcm works on the worktree path
+//
+// TestGcm_Worktree 测试添加、列出、锁定、删除和清理工作树
+// 验证返回的 Gcm 在工作树路径上工作
+func TestGcm_Worktree(t *testing.T) {
+	tempDIR := rese.V1(os.MkdirTemp("", "gitgo-worktree-*"))
+	defer func() {
+		must.Done(os.RemoveAll(tempDIR))
+	}()
+	repoPath := filepath.Join(tempDIR, "repo")
+	must.Done(os.MkdirAll(repoPath, 0755))
+
+	gcm := gitgo.New(repoPath)
+	gcm.Init().Done()
+	must.Done(os.WriteFile(filepath.Join(repoPath, "a.txt"), []byte("a"), 0644))
+	gcm.Add().Commit("initial").Done()
+
+	featurePath := filepath.Join(tempDIR, "feature")
+	featureGcm := gcm.WorktreeAdd(featurePath, "main", gitgo.WorktreeAddOptions{NewBranch: "feature"})
+	require.NoError(t, featureGcm.Reason())
+	require.Equal(t, "feature", rese.C1(featureGcm.GetCurrentBranch()))
+	must.Done(os.WriteFile(filepath.Join(featurePath, "b.txt"), []byte("b"), 0644))
+	featureGcm.Add().Commit("feature work").Done()
+
+	detachedGcm := gcm.WorktreeAdd("../detached", "feature", gitgo.WorktreeAddOptions{Detach: true, Lock: true, LockReason: "ci job"})
+	require.NoError(t, detachedGcm.Reason())
+	require.FileExists(t, filepath.Join(tempDIR, "detached", "b.txt"))
+
+	detachedPath := filepath.Join(tempDIR, "detached")
+	worktrees := rese.V1(gcm.ListWorktrees())
+	require.Len(t, worktrees, 3)
+	require.Equal(t, "main", worktrees[0].Branch)
+	byPath := map[string]*gitgo.Worktree{}
+	for idx := range worktrees {
+		byPath[worktrees[idx].Path] = &worktrees[idx]
+	}
+	require.Contains(t, byPath, featurePath)
+	require.Contains(t, byPath, detachedPath)
+	require.Equal(t, "feature", byPath[featurePath].Branch)
+	require.Equal(t, rese.V1(featureGcm.GetCurrentCommitHash()), byPath[featurePath].Head)
+	require.Equal(t, "feature work", rese.V1(byPath[featurePath].Gcm().GetCommitMessage("HEAD")))
+	require.True(t, byPath[detachedPath].Detached)
+	require.True(t, byPath[detachedPath].Locked)
+	require.Equal(t, "ci job", byPath[detachedPath].LockReason)
+
+	require.Error(t, gcm.WorktreeRemove(detachedPath).Reason())
+	gcm.WorktreeUnlock(detachedPath).WorktreeRemove(detachedPath).Done()
+
+	gcm.WorktreeLock(featurePath, "").Done()
+	require.True(t, rese.V1(gcm.ListWorktrees())[1].Locked)
+	require.Empty(t, rese.V1(gcm.ListWorktrees())[1].LockReason)
+	gcm.WorktreeUnlock(featurePath).Done()
+
+	must.Done(os.WriteFile(filepath.Join(featurePath, "dirty.txt"), []byte("dirty"), 0644))
+	require.Error(t, gcm.WorktreeRemove(featurePath).Reason())
+	gcm.WorktreeRemoveForce(featurePath).Done()
+	require.Len(t, rese.V1(gcm.ListWorktrees()), 1)
+
+	stalePath := filepath.Join(tempDIR, "stale")
+	gcm.WorktreeAdd(stalePath, "feature", gitgo.WorktreeAddOptions{}).Done()
+	must.Done(os.RemoveAll(stalePath))
+	worktrees = rese.V1(gcm.ListWorktrees())
+	require.Len(t, worktrees, 2)
+	require.True(t, worktrees[1].Prunable)
+	gcm.WorktreePrune().Done()
+	require.Len(t, rese.V1(gcm.ListWorktrees()), 1)
+}